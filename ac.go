@@ -0,0 +1,196 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Temperature range accepted by ACSetAll, in degrees Celsius. Most IR air
+// conditioners support 16–30.
+const (
+	ACMinTemperature = 16
+	ACMaxTemperature = 30
+)
+
+// ACMode is the operating mode of an IR air conditioner.
+type ACMode int
+
+const (
+	ACModeAuto ACMode = 1
+	ACModeCool ACMode = 2
+	ACModeDry  ACMode = 3
+	ACModeFan  ACMode = 4
+	ACModeHeat ACMode = 5
+)
+
+// ACFan is the fan speed of an IR air conditioner.
+type ACFan int
+
+const (
+	ACFanAuto   ACFan = 1
+	ACFanLow    ACFan = 2
+	ACFanMedium ACFan = 3
+	ACFanHigh   ACFan = 4
+)
+
+// ACSettings is the full state sent to an IR air conditioner by ACSetAll.
+type ACSettings struct {
+	// Temperature is the target, in Unit.
+	Temperature int
+	Mode        ACMode
+	Fan         ACFan
+	Power       bool
+
+	// Unit is the unit of Temperature. ACSetAll takes an empty Unit to mean
+	// the client's WithTemperatureUnit, and elsewhere it means Celsius.
+	Unit TemperatureUnit
+}
+
+// inCelsius returns s with the temperature in whole degrees Celsius. A
+// Fahrenheit temperature is rounded to the nearest degree and clamped to
+// ACMinTemperature and ACMaxTemperature, since IR air conditioners only take
+// whole Celsius values.
+func (s ACSettings) inCelsius() ACSettings {
+	if s.Unit == Fahrenheit {
+		s.Temperature = min(max(roundCelsius(s.Temperature, Fahrenheit), ACMinTemperature), ACMaxTemperature)
+	}
+	s.Unit = Celsius
+	return s
+}
+
+// String returns the setAll parameter "temp,mode,fan,power", e.g.
+// "26,2,3,on", with the temperature in Celsius.
+func (s ACSettings) String() string {
+	s = s.inCelsius()
+	power := PowerOff
+	if s.Power {
+		power = PowerOn
+	}
+	return fmt.Sprintf("%d,%d,%d,%s", s.Temperature, s.Mode, s.Fan, power)
+}
+
+// Validate checks the temperature is within ACMinTemperature and
+// ACMaxTemperature and that the mode and fan speed are known values.
+// Fahrenheit temperatures are converted and clamped first, so only Celsius
+// temperatures can be out of range.
+func (s ACSettings) Validate() error {
+	s = s.inCelsius()
+	if s.Temperature < ACMinTemperature || s.Temperature > ACMaxTemperature {
+		return fmt.Errorf("%w: AC temperature must be between %d and %d, got %d", ErrInvalidParameter, ACMinTemperature, ACMaxTemperature, s.Temperature)
+	}
+	if s.Mode < ACModeAuto || s.Mode > ACModeHeat {
+		return fmt.Errorf("%w: unknown AC mode %d", ErrInvalidParameter, s.Mode)
+	}
+	if s.Fan < ACFanAuto || s.Fan > ACFanHigh {
+		return fmt.Errorf("%w: unknown AC fan speed %d", ErrInvalidParameter, s.Fan)
+	}
+	return nil
+}
+
+// ACSetAll sends the complete state s to the IR air conditioner remote with
+// the given id. On success s is remembered, converted to Celsius, as the
+// remote's LastACState.
+func (c *Client) ACSetAll(ctx context.Context, remoteID string, s ACSettings) error {
+	unlock := c.acStates.lock(remoteID)
+	defer unlock()
+	return c.acSetAll(ctx, remoteID, s)
+}
+
+// acSetAll is ACSetAll without taking the remote's lock.
+func (c *Client) acSetAll(ctx context.Context, remoteID string, s ACSettings) error {
+	if s.Unit == "" {
+		s.Unit = c.temperatureUnit
+	}
+	s = s.inCelsius()
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	if err := c.SendCommand(ctx, remoteID, Command{Command: "setAll", Parameter: s.String()}); err != nil {
+		return err
+	}
+	c.acStates.put(remoteID, s)
+	return nil
+}
+
+// LastACState returns the settings last sent to the remote with ACSetAll by
+// this client. IR is one-way, so this is the only record of the AC's state;
+// it is lost when the process exits and is wrong if the AC's own remote has
+// been used since.
+func (c *Client) LastACState(remoteID string) (ACSettings, bool) {
+	return c.acStates.get(remoteID)
+}
+
+// UpdateACState applies mutate to a copy of the remote's LastACState and
+// sends the result with ACSetAll, e.g. to change the mode while keeping the
+// temperature. The settings passed to mutate are in Celsius. Updates and
+// ACSetAll calls for the same remote are serialised, so concurrent updates
+// don't lose each other's changes. It returns ErrNoACState if no state has
+// been sent yet.
+func (c *Client) UpdateACState(ctx context.Context, remoteID string, mutate func(*ACSettings)) error {
+	unlock := c.acStates.lock(remoteID)
+	defer unlock()
+
+	s, ok := c.acStates.get(remoteID)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNoACState, remoteID)
+	}
+	mutate(&s)
+	return c.acSetAll(ctx, remoteID, s)
+}
+
+// ACAdjustTemperature changes the target temperature of the remote's
+// LastACState by delta degrees Celsius, clamped to ACMinTemperature and
+// ACMaxTemperature, and resends the full state. It returns ErrNoACState if
+// no state has been sent yet.
+func (c *Client) ACAdjustTemperature(ctx context.Context, remoteID string, delta int) error {
+	return c.UpdateACState(ctx, remoteID, func(s *ACSettings) {
+		s.Temperature = min(max(s.Temperature+delta, ACMinTemperature), ACMaxTemperature)
+	})
+}
+
+// ErrNoACState is returned when an AC helper needs the last sent state of a
+// remote and none is known.
+var ErrNoACState = errors.New("no known state for AC remote; send one with ACSetAll first")
+
+// acStateStore remembers the last settings sent per AC remote, and holds a
+// lock per remote that is kept across sending a state and storing it.
+type acStateStore struct {
+	mu     sync.Mutex
+	states map[string]ACSettings
+	locks  map[string]*sync.Mutex
+}
+
+// lock locks the remote and returns the function that unlocks it.
+func (st *acStateStore) lock(remoteID string) func() {
+	st.mu.Lock()
+	if st.locks == nil {
+		st.locks = make(map[string]*sync.Mutex)
+	}
+	l, ok := st.locks[remoteID]
+	if !ok {
+		l = new(sync.Mutex)
+		st.locks[remoteID] = l
+	}
+	st.mu.Unlock()
+
+	l.Lock()
+	return l.Unlock
+}
+
+func (st *acStateStore) get(remoteID string) (ACSettings, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	s, ok := st.states[remoteID]
+	return s, ok
+}
+
+func (st *acStateStore) put(remoteID string, s ACSettings) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.states == nil {
+		st.states = make(map[string]ACSettings)
+	}
+	st.states[remoteID] = s
+}
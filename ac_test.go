@@ -0,0 +1,183 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestACSettingsString(t *testing.T) {
+	tests := []struct {
+		s    ACSettings
+		want string
+	}{
+		{ACSettings{Temperature: 26, Mode: ACModeCool, Fan: ACFanMedium, Power: true}, "26,2,3,on"},
+		{ACSettings{Temperature: 16, Mode: ACModeHeat, Fan: ACFanAuto}, "16,5,1,off"},
+		{ACSettings{Temperature: 72, Mode: ACModeAuto, Fan: ACFanLow, Power: true, Unit: Fahrenheit}, "22,1,2,on"},
+	}
+	for _, tt := range tests {
+		if got := tt.s.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestACSettingsValidate(t *testing.T) {
+	valid := ACSettings{Temperature: 24, Mode: ACModeCool, Fan: ACFanAuto, Power: true}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("valid settings: %v", err)
+	}
+
+	invalid := map[string]ACSettings{
+		"too cold":     {Temperature: 15, Mode: ACModeCool, Fan: ACFanAuto},
+		"too hot":      {Temperature: 31, Mode: ACModeCool, Fan: ACFanAuto},
+		"no mode":      {Temperature: 24, Fan: ACFanAuto},
+		"unknown mode": {Temperature: 24, Mode: 6, Fan: ACFanAuto},
+		"no fan":       {Temperature: 24, Mode: ACModeCool},
+		"unknown fan":  {Temperature: 24, Mode: ACModeCool, Fan: 5},
+	}
+	for name, s := range invalid {
+		if err := s.Validate(); !errors.Is(err, ErrInvalidParameter) {
+			t.Errorf("%s: err = %v, want ErrInvalidParameter", name, err)
+		}
+	}
+}
+
+func TestACSetAll(t *testing.T) {
+	c, srv := newCommandClient(t)
+	ctx := context.Background()
+
+	s := ACSettings{Temperature: 25, Mode: ACModeDry, Fan: ACFanHigh, Power: true}
+	if err := c.ACSetAll(ctx, "IR000000002", s); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ACSetAll(ctx, "IR000000002", ACSettings{Temperature: 40, Mode: ACModeCool, Fan: ACFanAuto}); !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("40°C: err = %v, want ErrInvalidParameter", err)
+	}
+	wantCommands(t, srv.commands(), sentCommand{"IR000000002", Command{"setAll", "25,3,4,on", CommandTypeCommand}})
+
+	last, ok := c.LastACState("IR000000002")
+	if !ok || last.Temperature != 25 || last.Mode != ACModeDry {
+		t.Errorf("LastACState = %+v, %v", last, ok)
+	}
+}
+
+func TestACAdjustTemperature(t *testing.T) {
+	c, srv := newCommandClient(t)
+	ctx := context.Background()
+
+	if err := c.ACAdjustTemperature(ctx, "IR000000002", 1); !errors.Is(err, ErrNoACState) {
+		t.Fatalf("no prior state: err = %v, want ErrNoACState", err)
+	}
+
+	if err := c.ACSetAll(ctx, "IR000000002", ACSettings{Temperature: 29, Mode: ACModeCool, Fan: ACFanAuto, Power: true}); err != nil {
+		t.Fatal(err)
+	}
+	for _, delta := range []int{1, 5, -20} {
+		if err := c.ACAdjustTemperature(ctx, "IR000000002", delta); err != nil {
+			t.Fatalf("delta %d: %v", delta, err)
+		}
+	}
+
+	want := []sentCommand{
+		{"IR000000002", Command{"setAll", "29,2,1,on", CommandTypeCommand}},
+		{"IR000000002", Command{"setAll", "30,2,1,on", CommandTypeCommand}},
+		{"IR000000002", Command{"setAll", "30,2,1,on", CommandTypeCommand}},
+		{"IR000000002", Command{"setAll", "16,2,1,on", CommandTypeCommand}},
+	}
+	wantCommands(t, srv.commands(), want...)
+}
+
+func TestACSetAllFahrenheit(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		s    ACSettings
+		want string
+	}{
+		{"rounds down", nil, ACSettings{Temperature: 70, Unit: Fahrenheit}, "21,2,1,on"},
+		{"rounds up", nil, ACSettings{Temperature: 71, Unit: Fahrenheit}, "22,2,1,on"},
+		{"clamps low", nil, ACSettings{Temperature: 50, Unit: Fahrenheit}, "16,2,1,on"},
+		{"clamps high", nil, ACSettings{Temperature: 95, Unit: Fahrenheit}, "30,2,1,on"},
+		{"client unit", []Option{WithTemperatureUnit(Fahrenheit)}, ACSettings{Temperature: 75}, "24,2,1,on"},
+		{"explicit Celsius", []Option{WithTemperatureUnit(Fahrenheit)}, ACSettings{Temperature: 24, Unit: Celsius}, "24,2,1,on"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, srv := newCommandClient(t, tt.opts...)
+			s := tt.s
+			s.Mode, s.Fan, s.Power = ACModeCool, ACFanAuto, true
+			if err := c.ACSetAll(context.Background(), "IR000000002", s); err != nil {
+				t.Fatal(err)
+			}
+			wantCommands(t, srv.commands(), sentCommand{"IR000000002", Command{"setAll", tt.want, CommandTypeCommand}})
+			if last, _ := c.LastACState("IR000000002"); last.Unit != Celsius {
+				t.Errorf("LastACState unit = %q, want Celsius", last.Unit)
+			}
+		})
+	}
+}
+
+func TestACSetAllCelsiusNotClamped(t *testing.T) {
+	c, srv := newCommandClient(t)
+	err := c.ACSetAll(context.Background(), "IR000000002", ACSettings{Temperature: 35, Mode: ACModeCool, Fan: ACFanAuto, Unit: Celsius})
+	if !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("35°C: err = %v, want ErrInvalidParameter", err)
+	}
+	wantCommands(t, srv.commands())
+}
+
+func TestUpdateACState(t *testing.T) {
+	c, srv := newCommandClient(t)
+	ctx := context.Background()
+
+	if err := c.UpdateACState(ctx, "IR000000002", func(s *ACSettings) { s.Mode = ACModeHeat }); !errors.Is(err, ErrNoACState) {
+		t.Fatalf("no prior state: err = %v, want ErrNoACState", err)
+	}
+
+	if err := c.ACSetAll(ctx, "IR000000002", ACSettings{Temperature: 23, Mode: ACModeCool, Fan: ACFanLow, Power: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.UpdateACState(ctx, "IR000000002", func(s *ACSettings) { s.Mode = ACModeHeat }); err != nil {
+		t.Fatal(err)
+	}
+	wantCommands(t, srv.commands(),
+		sentCommand{"IR000000002", Command{"setAll", "23,2,2,on", CommandTypeCommand}},
+		sentCommand{"IR000000002", Command{"setAll", "23,5,2,on", CommandTypeCommand}},
+	)
+	if last, _ := c.LastACState("IR000000002"); last.Mode != ACModeHeat || last.Temperature != 23 {
+		t.Errorf("LastACState = %+v, want heat at 23", last)
+	}
+
+	// An invalid mutation isn't sent or stored
+	if err := c.UpdateACState(ctx, "IR000000002", func(s *ACSettings) { s.Temperature = 40 }); !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("40°C: err = %v, want ErrInvalidParameter", err)
+	}
+	if last, _ := c.LastACState("IR000000002"); last.Temperature != 23 {
+		t.Errorf("LastACState temperature = %d after a rejected update, want 23", last.Temperature)
+	}
+}
+
+func TestUpdateACStateConcurrent(t *testing.T) {
+	c, _ := newCommandClient(t)
+	ctx := context.Background()
+	if err := c.ACSetAll(ctx, "IR000000002", ACSettings{Temperature: ACMinTemperature, Mode: ACModeHeat, Fan: ACFanAuto, Power: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.UpdateACState(ctx, "IR000000002", func(s *ACSettings) { s.Temperature++ }); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if last, _ := c.LastACState("IR000000002"); last.Temperature != ACMinTemperature+10 {
+		t.Errorf("temperature = %d after 10 concurrent increments, want %d", last.Temperature, ACMinTemperature+10)
+	}
+}
@@ -0,0 +1,92 @@
+package switchbot
+
+import (
+	"context"
+	"fmt"
+)
+
+// CommandResult is the outcome of one command sent by a batch helper.
+type CommandResult struct {
+	DeviceID   string
+	DeviceName string
+	Command    Command
+	Err        error
+}
+
+// DefaultOffPolicy returns the command AllOff sends per device type: power
+// off for plugs, lights, bots, humidifiers, fans and air purifiers, close for
+// curtains and blind tilts, and lock for locks. The map is a fresh copy the
+// caller may modify.
+//
+// IR remotes aren't included: not every learned remote has a power-off key.
+// Add DeviceTypeInfraredRemote to a custom policy to include them.
+func DefaultOffPolicy() map[DeviceType]Command {
+	off := Command{Command: "turnOff", Parameter: DefaultParameter}
+	return map[DeviceType]Command{
+		DeviceTypeBot:                  off,
+		DeviceTypePlug:                 off,
+		DeviceTypePlugMiniUS:           off,
+		DeviceTypePlugMiniJP:           off,
+		DeviceTypeColorBulb:            off,
+		DeviceTypeStripLight:           off,
+		DeviceTypeStripLight3:          off,
+		DeviceTypeFloorLamp:            off,
+		DeviceTypeCeilingLight:         off,
+		DeviceTypeCeilingLightPro:      off,
+		DeviceTypeHumidifier:           off,
+		DeviceTypeCirculatorFan:        off,
+		DeviceTypeAirPurifierVOC:       off,
+		DeviceTypeAirPurifierPM25:      off,
+		DeviceTypeAirPurifierTableVOC:  off,
+		DeviceTypeAirPurifierTablePM25: off,
+		DeviceTypeCurtain:              off,
+		DeviceTypeCurtain3:             off,
+		DeviceTypeBlindTilt:            {Command: "closeDown", Parameter: DefaultParameter},
+		DeviceTypeLock:                 {Command: "lock", Parameter: DefaultParameter},
+		DeviceTypeLockPro:              {Command: "lock", Parameter: DefaultParameter},
+	}
+}
+
+// AllOff sends every controllable device its command from DefaultOffPolicy.
+// See AllOffWith.
+func (c *Client) AllOff(ctx context.Context) []CommandResult {
+	return c.AllOffWith(ctx, DefaultOffPolicy())
+}
+
+// AllOffWith sends every controllable device the command policy lists for its
+// type; devices whose type isn't in policy are skipped. Commands run
+// concurrently, bounded by the client's concurrency and rate limit, and one
+// result is returned per device commanded. If the device list can't be
+// fetched the only result carries that error.
+func (c *Client) AllOffWith(ctx context.Context, policy map[DeviceType]Command) []CommandResult {
+	devices, err := c.ControllableDevices(ctx)
+	if err != nil {
+		return []CommandResult{{Err: err}}
+	}
+
+	var results []CommandResult
+	for _, d := range devices {
+		if cmd, ok := policy[d.DeviceType]; ok {
+			results = append(results, CommandResult{DeviceID: d.DeviceID, DeviceName: d.DeviceName, Command: cmd})
+		}
+	}
+
+	c.forEach(len(results), func(i int) {
+		results[i].Err = c.SendCommand(ctx, results[i].DeviceID, results[i].Command)
+	})
+	return results
+}
+
+// CommandByType sends cmd to every device of type t, e.g. "turnOff" to all
+// DeviceTypePlugMiniUS plugs. Use DeviceTypeInfraredRemote for IR remotes.
+// The command must be one Capabilities lists for t, unless it is a
+// CommandTypeCustomize button; otherwise nothing is sent and the only result
+// carries an error wrapping ErrInvalidParameter. Commands run as in
+// AllOffWith, with one result per device and none if there are no devices of
+// type t.
+func (c *Client) CommandByType(ctx context.Context, t DeviceType, cmd Command) []CommandResult {
+	if cmd.CommandType != CommandTypeCustomize && !Supports(t, Capability(cmd.Command)) {
+		return []CommandResult{{Command: cmd, Err: fmt.Errorf("%w: %s does not accept %q", ErrInvalidParameter, t, cmd.Command)}}
+	}
+	return c.AllOffWith(ctx, map[DeviceType]Command{t: cmd})
+}
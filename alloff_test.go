@@ -0,0 +1,132 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestDefaultOffPolicyCoversControllableTypes(t *testing.T) {
+	policy := DefaultOffPolicy()
+	for typ := range controllableTypes {
+		if _, ok := policy[typ]; !ok {
+			t.Errorf("DefaultOffPolicy has no command for %s", typ)
+		}
+	}
+}
+
+func TestAllOff(t *testing.T) {
+	devices := []Device{
+		{DeviceID: "DEV000000001", DeviceType: DeviceTypeBot},
+		{DeviceID: "DEV000000002", DeviceType: DeviceTypeCurtain},
+		{DeviceID: "DEV000000004", DeviceType: DeviceTypeMeterPlus},
+		{DeviceID: "DEV000000005", DeviceType: DeviceTypeBlindTilt},
+		{DeviceID: "DEV000000006", DeviceType: DeviceTypeLock},
+		{DeviceID: "DEV000000019", DeviceType: DeviceTypeCirculatorFan},
+		{DeviceID: "DEV000000020", DeviceType: DeviceTypeAirPurifierPM25},
+	}
+	remotes := []InfraredRemote{{DeviceID: "IR000000001", RemoteType: "TV"}}
+	c, srv := newDeviceClient(t, devices, remotes)
+
+	results := c.AllOff(context.Background())
+
+	want := map[string]string{
+		"DEV000000001": "turnOff",
+		"DEV000000002": "turnOff",
+		"DEV000000005": "closeDown",
+		"DEV000000006": "lock",
+		"DEV000000019": "turnOff",
+		"DEV000000020": "turnOff",
+	}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results %+v, want %d", len(results), results, len(want))
+	}
+	for _, r := range results {
+		if r.Err != nil {
+			t.Errorf("%s: %v", r.DeviceID, r.Err)
+		}
+		if want[r.DeviceID] != r.Command.Command {
+			t.Errorf("%s: result command %q, want %q", r.DeviceID, r.Command.Command, want[r.DeviceID])
+		}
+	}
+	sent := srv.commands()
+	if len(sent) != len(want) {
+		t.Fatalf("sent %d commands %+v, want %d", len(sent), sent, len(want))
+	}
+	for _, cmd := range sent {
+		if want[cmd.DeviceID] != cmd.Command.Command {
+			t.Errorf("%s: sent %q, want %q", cmd.DeviceID, cmd.Command.Command, want[cmd.DeviceID])
+		}
+	}
+}
+
+func TestAllOffWithCustomPolicy(t *testing.T) {
+	devices := []Device{
+		{DeviceID: "DEV000000001", DeviceType: DeviceTypeBot},
+		{DeviceID: "DEV000000006", DeviceType: DeviceTypeLock},
+	}
+	remotes := []InfraredRemote{{DeviceID: "IR000000001", RemoteType: "TV"}}
+	c, srv := newDeviceClient(t, devices, remotes)
+
+	results := c.AllOffWith(context.Background(), map[DeviceType]Command{
+		DeviceTypeInfraredRemote: {Command: "turnOff", Parameter: DefaultParameter},
+	})
+	if len(results) != 1 || results[0].DeviceID != "IR000000001" || results[0].Err != nil {
+		t.Fatalf("results = %+v, want only the IR remote", results)
+	}
+	wantCommands(t, srv.commands(), sentCommand{"IR000000001", Command{"turnOff", DefaultParameter, CommandTypeCommand}})
+}
+
+func TestCommandByType(t *testing.T) {
+	devices := []Device{
+		{DeviceID: "PLUG01", DeviceName: "Desk", DeviceType: DeviceTypePlugMiniUS},
+		{DeviceID: "BOT01", DeviceName: "Kettle", DeviceType: DeviceTypeBot},
+		{DeviceID: "PLUG02", DeviceName: "Heater", DeviceType: DeviceTypePlugMiniUS},
+		{DeviceID: "PLUG03", DeviceName: "Old", DeviceType: DeviceTypePlug},
+	}
+	c, srv := newDeviceClient(t, devices, nil)
+
+	results := c.CommandByType(context.Background(), DeviceTypePlugMiniUS, Command{Command: "turnOff"})
+	if len(results) != 2 {
+		t.Fatalf("results = %+v, want one per Plug Mini (US)", results)
+	}
+	got := map[string]bool{}
+	for _, r := range results {
+		if r.Err != nil {
+			t.Errorf("%s: %v", r.DeviceID, r.Err)
+		}
+		got[r.DeviceID] = true
+	}
+	if !got["PLUG01"] || !got["PLUG02"] {
+		t.Errorf("results for %v, want PLUG01 and PLUG02", got)
+	}
+	sent := srv.commands()
+	if len(sent) != 2 {
+		t.Fatalf("sent %+v, want two turnOff commands", sent)
+	}
+	for _, s := range sent {
+		if s.Command.Command != "turnOff" || (s.DeviceID != "PLUG01" && s.DeviceID != "PLUG02") {
+			t.Errorf("sent %+v", s)
+		}
+	}
+}
+
+func TestCommandByTypeValidates(t *testing.T) {
+	c, srv := newDeviceClient(t, []Device{{DeviceID: "PLUG01", DeviceType: DeviceTypePlug}}, []InfraredRemote{{DeviceID: "IR01", RemoteType: "Projector"}})
+	ctx := context.Background()
+
+	results := c.CommandByType(ctx, DeviceTypePlug, Command{Command: "setColor", Parameter: "255:0:0"})
+	if len(results) != 1 || !errors.Is(results[0].Err, ErrInvalidParameter) {
+		t.Errorf("unsupported command: results = %+v, want one ErrInvalidParameter", results)
+	}
+	wantCommands(t, srv.commands())
+
+	results = c.CommandByType(ctx, DeviceTypeInfraredRemote, Command{Command: "Lens", CommandType: CommandTypeCustomize})
+	if len(results) != 1 || results[0].Err != nil || results[0].DeviceID != "IR01" {
+		t.Errorf("custom IR button: results = %+v", results)
+	}
+
+	if results := c.CommandByType(ctx, DeviceTypeLock, Command{Command: "lock"}); len(results) != 0 {
+		t.Errorf("no locks: results = %+v, want none", results)
+	}
+}
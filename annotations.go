@@ -0,0 +1,123 @@
+package switchbot
+
+import (
+	"maps"
+	"sync"
+)
+
+// annotationStore holds the annotations set with SetAnnotation. The zero
+// value is ready to use.
+type annotationStore struct {
+	mu   sync.Mutex
+	byID map[string]map[string]string
+
+	// dirty is set once annotations are set, removed or restored, so
+	// SaveCache writes them out even after the last one is removed.
+	dirty bool
+}
+
+// SetAnnotation attaches a local key/value annotation, such as "room" or
+// "tags", to a device or IR remote, to organise devices beyond what the
+// SwitchBot app offers. An empty value removes the key. Annotations live in
+// the client only, never reach SwitchBot, and are filled into the
+// Annotations field of the devices and remotes returned by Devices,
+// DevicesIter, ControllableDevices, HubDevices, InfraredRemotes,
+// IRRemotesByCategory and Inventory; SaveCache and LoadCache persist them.
+func (c *Client) SetAnnotation(id, key, value string) {
+	s := &c.annotations
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.dirty = true
+	if value == "" {
+		delete(s.byID[id], key)
+		if len(s.byID[id]) == 0 {
+			delete(s.byID, id)
+		}
+		return
+	}
+	if s.byID == nil {
+		s.byID = make(map[string]map[string]string)
+	}
+	if s.byID[id] == nil {
+		s.byID[id] = make(map[string]string)
+	}
+	s.byID[id][key] = value
+}
+
+// Annotations returns a copy of the annotations of a device, or nil if it
+// has none.
+func (c *Client) Annotations(id string) map[string]string {
+	s := &c.annotations
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return maps.Clone(s.byID[id])
+}
+
+// annotate fills in the Annotations of devices, which must not be shared
+// with the device cache.
+func (c *Client) annotate(devices []Device) {
+	s := &c.annotations
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range devices {
+		devices[i].Annotations = maps.Clone(s.byID[devices[i].DeviceID])
+	}
+}
+
+// annotateRemotes is annotate for IR remotes.
+func (c *Client) annotateRemotes(remotes []InfraredRemote) {
+	s := &c.annotations
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range remotes {
+		remotes[i].Annotations = maps.Clone(s.byID[remotes[i].DeviceID])
+	}
+}
+
+// all returns a deep copy of every annotation, or nil if there are none.
+func (s *annotationStore) all() map[string]map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.byID) == 0 {
+		return nil
+	}
+	out := make(map[string]map[string]string, len(s.byID))
+	for id, a := range s.byID {
+		out[id] = maps.Clone(a)
+	}
+	return out
+}
+
+// changed reports whether annotations were set, removed or restored.
+func (s *annotationStore) changed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dirty
+}
+
+// merge adds annotations restored from disk, keeping any key already set in
+// memory.
+func (s *annotationStore) merge(restored map[string]map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(restored) > 0 {
+		s.dirty = true
+	}
+	for id, a := range restored {
+		for key, value := range a {
+			if value == "" {
+				continue
+			}
+			if s.byID == nil {
+				s.byID = make(map[string]map[string]string)
+			}
+			if s.byID[id] == nil {
+				s.byID[id] = make(map[string]string)
+			}
+			if _, ok := s.byID[id][key]; !ok {
+				s.byID[id][key] = value
+			}
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package switchbot
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAnnotations(t *testing.T) {
+	c, _ := newDeviceClient(t, nil, nil)
+
+	c.SetAnnotation("BOT01", "room", "kitchen")
+	c.SetAnnotation("BOT01", "tags", "morning")
+	if got := c.Annotations("BOT01"); len(got) != 2 || got["room"] != "kitchen" || got["tags"] != "morning" {
+		t.Errorf("Annotations = %v, want room and tags", got)
+	}
+
+	c.Annotations("BOT01")["room"] = "changed"
+	if got := c.Annotations("BOT01")["room"]; got != "kitchen" {
+		t.Errorf("room = %q after changing the returned map, want kitchen", got)
+	}
+
+	c.SetAnnotation("BOT01", "room", "")
+	c.SetAnnotation("BOT01", "tags", "")
+	if got := c.Annotations("BOT01"); got != nil {
+		t.Errorf("Annotations = %v after removing every key, want nil", got)
+	}
+	if got := c.Annotations("UNKNOWN"); got != nil {
+		t.Errorf("Annotations of an unannotated id = %v, want nil", got)
+	}
+}
+
+func TestAnnotationsFilledIntoListings(t *testing.T) {
+	devices := []Device{
+		{DeviceID: "HUB01", DeviceType: DeviceTypeHubMini},
+		{DeviceID: "BOT01", DeviceType: DeviceTypeBot, HubDeviceID: "HUB01"},
+	}
+	remotes := []InfraredRemote{{DeviceID: "TV01", RemoteType: "TV", HubDeviceID: "HUB01"}}
+	c, _ := newDeviceClient(t, devices, remotes, WithDeviceCache(time.Minute))
+	c.SetAnnotation("BOT01", "room", "kitchen")
+	c.SetAnnotation("TV01", "room", "lounge")
+	ctx := context.Background()
+
+	wantDevices := func(name string, got []Device, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		for _, d := range got {
+			want := map[string]string{"BOT01": "kitchen", "TV01": "lounge"}[d.DeviceID]
+			if d.Annotations["room"] != want {
+				t.Errorf("%s: %s room = %q, want %q", name, d.DeviceID, d.Annotations["room"], want)
+			}
+		}
+	}
+	wantRemotes := func(name string, got []InfraredRemote, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if len(got) != 1 || got[0].Annotations["room"] != "lounge" {
+			t.Errorf("%s = %+v, want TV01 annotated with room lounge", name, got)
+		}
+	}
+
+	listed, err := c.Devices(ctx)
+	wantDevices("Devices", listed, err)
+	controllable, err := c.ControllableDevices(ctx)
+	wantDevices("ControllableDevices", controllable, err)
+	onHub, err := c.HubDevices(ctx, "HUB01")
+	wantDevices("HubDevices", onHub, err)
+	if len(onHub) != 2 {
+		t.Errorf("HubDevices = %+v, want the bot and the remote", onHub)
+	}
+	var iterated []Device
+	it := c.DevicesIter(ctx)
+	for it.Next() {
+		iterated = append(iterated, it.Value())
+	}
+	wantDevices("DevicesIter", iterated, it.Err())
+
+	irRemotes, err := c.InfraredRemotes(ctx)
+	wantRemotes("InfraredRemotes", irRemotes, err)
+	tvs, err := c.IRRemotesByCategory(ctx, "TV")
+	wantRemotes("IRRemotesByCategory", tvs, err)
+
+	inv, err := c.Inventory(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantDevices("Inventory.Devices", inv.Devices, nil)
+	wantDevices("Inventory.ByType", inv.ByType[DeviceTypeBot], nil)
+	wantRemotes("Inventory.InfraredRemotes", inv.InfraredRemotes, nil)
+	wantRemotes("Inventory.RemotesByCategory", inv.RemotesByCategory["TV"], nil)
+}
+
+func TestAnnotationsDontReachDeviceCache(t *testing.T) {
+	remotes := []InfraredRemote{{DeviceID: "TV01", RemoteType: "TV"}}
+	c, _ := newDeviceClient(t, []Device{{DeviceID: "BOT01", DeviceType: DeviceTypeBot}}, remotes, WithDeviceCache(time.Minute))
+	c.SetAnnotation("BOT01", "room", "kitchen")
+	c.SetAnnotation("TV01", "room", "lounge")
+	ctx := context.Background()
+
+	devices, err := c.Devices(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	devices[0].Annotations["room"] = "changed"
+	irRemotes, err := c.InfraredRemotes(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	irRemotes[0].Annotations["room"] = "changed"
+
+	c.SetAnnotation("BOT01", "room", "")
+	c.SetAnnotation("TV01", "room", "")
+	if devices, _ := c.Devices(ctx); devices[0].Annotations != nil {
+		t.Errorf("Devices annotations = %v after removal, want none", devices[0].Annotations)
+	}
+	if irRemotes, _ := c.InfraredRemotes(ctx); irRemotes[0].Annotations != nil {
+		t.Errorf("InfraredRemotes annotations = %v after removal, want none", irRemotes[0].Annotations)
+	}
+}
@@ -0,0 +1,20 @@
+package switchbot
+
+import "sync"
+
+// forEach calls fn for every index in [0, n) using at most c.concurrency
+// goroutines, and returns once all calls have finished.
+func (c *Client) forEach(n int, fn func(i int)) {
+	sem := make(chan struct{}, c.concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}()
+	}
+	wg.Wait()
+}
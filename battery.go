@@ -0,0 +1,16 @@
+package switchbot
+
+// Battery is a battery charge as SwitchBot reports it, in percent. It
+// encodes to and from JSON as the plain number.
+type Battery int
+
+// Percent returns the charge in percent, 0 to 100.
+func (b Battery) Percent() int {
+	return int(b)
+}
+
+// Fraction returns the charge as a fraction from 0.0 to 1.0. Out of range
+// readings are clamped.
+func (b Battery) Fraction() float64 {
+	return min(max(float64(b)/100, 0), 1)
+}
@@ -0,0 +1,49 @@
+package switchbot
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestBattery(t *testing.T) {
+	tests := []struct {
+		b        Battery
+		percent  int
+		fraction float64
+	}{
+		{0, 0, 0},
+		{45, 45, 0.45},
+		{100, 100, 1},
+		{120, 120, 1},
+		{-5, -5, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.b.Percent(); got != tt.percent {
+			t.Errorf("Battery(%d).Percent() = %d, want %d", tt.b, got, tt.percent)
+		}
+		if got := tt.b.Fraction(); got != tt.fraction {
+			t.Errorf("Battery(%d).Fraction() = %v, want %v", tt.b, got, tt.fraction)
+		}
+	}
+}
+
+func TestBatteryJSON(t *testing.T) {
+	var s BotStatus
+	if err := json.Unmarshal([]byte(`{"deviceType":"Bot","battery":87}`), &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Battery.Percent() != 87 {
+		t.Errorf("battery = %d, want 87", s.Battery)
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil {
+		t.Fatal(err)
+	}
+	if m["battery"] != 87.0 {
+		t.Errorf("encoded battery = %v, want the raw percent 87", m["battery"])
+	}
+}
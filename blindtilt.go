@@ -0,0 +1,86 @@
+package switchbot
+
+import (
+	"context"
+	"fmt"
+)
+
+// TiltDirection is the direction a Blind Tilt's slats close towards.
+type TiltDirection string
+
+const (
+	TiltUp   TiltDirection = "up"
+	TiltDown TiltDirection = "down"
+)
+
+// BlindTiltStatus is the status of a Blind Tilt.
+//
+// SlidePosition runs from 0 (closed) to 100 (open) and Direction reports
+// which way the slats are tilted.
+type BlindTiltStatus struct {
+	BaseStatus
+	Version       string        `json:"version"`
+	Calibrate     bool          `json:"calibrate"`
+	Group         bool          `json:"group"`
+	Moving        bool          `json:"moving"`
+	Direction     TiltDirection `json:"direction"`
+	SlidePosition int           `json:"slidePosition"`
+	Battery       Battery       `json:"battery"`
+}
+
+// NeedsCalibration reports whether the Blind Tilt hasn't been calibrated, in
+// which case positions are unreliable. As with curtains, calibration can
+// only be started from the SwitchBot app.
+func (s *BlindTiltStatus) NeedsCalibration() bool {
+	return !s.Calibrate
+}
+
+// MarshalJSON adds the "type" field UnmarshalStatus uses to restore the
+// status.
+func (s BlindTiltStatus) MarshalJSON() ([]byte, error) {
+	type plain BlindTiltStatus
+	return marshalStatus(statusKindBlindTilt, plain(s))
+}
+
+// BlindTiltStatus fetches the status of the Blind Tilt with the given id.
+func (c *Client) BlindTiltStatus(ctx context.Context, id string) (*BlindTiltStatus, error) {
+	return typedStatus[BlindTiltStatus](ctx, c, id)
+}
+
+// BlindTiltSetPosition tilts the slats towards direction to the given
+// position.
+//
+// Unlike Curtain, whose setPosition parameter is "index,mode,position", a
+// Blind Tilt takes "direction;position" (e.g. "up;60"). SwitchBot only accepts
+// even positions between 0 (closed) and 100 (open).
+func (c *Client) BlindTiltSetPosition(ctx context.Context, id string, direction TiltDirection, position int) error {
+	if direction != TiltUp && direction != TiltDown {
+		return fmt.Errorf("%w: blind tilt direction must be %q or %q, got %q", ErrInvalidParameter, TiltUp, TiltDown, direction)
+	}
+	if position < 0 || position > 100 || position%2 != 0 {
+		return fmt.Errorf("%w: blind tilt position must be an even value between 0 and 100, got %d", ErrInvalidParameter, position)
+	}
+
+	return c.SendCommand(ctx, id, Command{
+		Command:   "setPosition",
+		Parameter: fmt.Sprintf("%s;%d", direction, position),
+	})
+}
+
+// BlindTiltOpen fully opens the Blind Tilt.
+func (c *Client) BlindTiltOpen(ctx context.Context, id string) error {
+	return c.SendCommand(ctx, id, Command{Command: "fullyOpen", Parameter: DefaultParameter})
+}
+
+// BlindTiltClose closes the Blind Tilt with the slats tilted towards
+// direction.
+func (c *Client) BlindTiltClose(ctx context.Context, id string, direction TiltDirection) error {
+	switch direction {
+	case TiltUp:
+		return c.SendCommand(ctx, id, Command{Command: "closeUp", Parameter: DefaultParameter})
+	case TiltDown:
+		return c.SendCommand(ctx, id, Command{Command: "closeDown", Parameter: DefaultParameter})
+	default:
+		return fmt.Errorf("%w: blind tilt direction must be %q or %q, got %q", ErrInvalidParameter, TiltUp, TiltDown, direction)
+	}
+}
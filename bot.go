@@ -0,0 +1,79 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// BotMode is the mode a Bot is configured in from the SwitchBot app.
+type BotMode string
+
+const (
+	BotModePress     BotMode = "pressMode"
+	BotModeSwitch    BotMode = "switchMode"
+	BotModeCustomize BotMode = "customizeMode"
+)
+
+// ErrBotStuck is returned by BotPressAndConfirm when the Bot doesn't report
+// returning to idle in time.
+var ErrBotStuck = errors.New("bot did not return to idle")
+
+// BotStatus is the status of a Bot.
+type BotStatus struct {
+	BaseStatus
+	Version    string     `json:"version"`
+	Power      PowerState `json:"power"`
+	Battery    Battery    `json:"battery"`
+	DeviceMode BotMode    `json:"deviceMode"`
+}
+
+// MarshalJSON adds the "type" field UnmarshalStatus uses to restore the
+// status.
+func (s BotStatus) MarshalJSON() ([]byte, error) {
+	type plain BotStatus
+	return marshalStatus(statusKindBot, plain(s))
+}
+
+// BotStatus fetches the status of the Bot with the given id.
+func (c *Client) BotStatus(ctx context.Context, id string) (*BotStatus, error) {
+	return typedStatus[BotStatus](ctx, c, id)
+}
+
+// BotPress presses the Bot.
+func (c *Client) BotPress(ctx context.Context, id string) error {
+	return c.SendCommand(ctx, id, Command{Command: "press", Parameter: DefaultParameter})
+}
+
+// BotTurnOn switches a Bot in switch mode on.
+func (c *Client) BotTurnOn(ctx context.Context, id string) error {
+	return c.SendCommand(ctx, id, Command{Command: "turnOn", Parameter: DefaultParameter})
+}
+
+// BotTurnOff switches a Bot in switch mode off.
+func (c *Client) BotTurnOff(ctx context.Context, id string) error {
+	return c.SendCommand(ctx, id, Command{Command: "turnOff", Parameter: DefaultParameter})
+}
+
+// BotPressAndConfirm presses the Bot, then polls its status until it reports
+// power "off", i.e. the arm is back at rest, returning ErrBotStuck if that
+// doesn't happen within timeout.
+//
+// Confirmation is best-effort. Not all firmware reports a power state for a
+// Bot in press mode; when the status has none the press is assumed to have
+// worked.
+func (c *Client) BotPressAndConfirm(ctx context.Context, id string, timeout time.Duration) error {
+	if err := c.BotPress(ctx, id); err != nil {
+		return err
+	}
+
+	_, err := c.WaitForState(ctx, id, func(status any) bool {
+		bot, ok := status.(*BotStatus)
+		return !ok || bot.Power != PowerOn
+	}, timeout)
+	if errors.Is(err, ErrStateTimeout) {
+		return fmt.Errorf("%w: %s", ErrBotStuck, id)
+	}
+	return err
+}
@@ -0,0 +1,91 @@
+package switchbot
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without sending a request while the circuit
+// breaker set up by WithCircuitBreaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker open: SwitchBot API failing")
+
+// breaker is a circuit breaker over request attempts. It opens after
+// threshold consecutive failures, rejects attempts until cooldown has passed,
+// then lets a single probe through: a success closes it again and a failure
+// reopens it. A nil *breaker never rejects anything.
+//
+// Failures are the attempts send would retry: transport errors, 429s and
+// 5xx responses, as well as attempts cut short by a deadline. Any other
+// answer means the API is up, even if the request itself was rejected.
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time // zero while closed
+	probing  bool
+}
+
+func newBreaker(threshold int, cooldown time.Duration) *breaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &breaker{threshold: threshold, cooldown: cooldown}
+}
+
+// allow reports whether an attempt may be made now, returning ErrCircuitOpen
+// if not. Once the cooldown has passed, only the first caller is let through
+// as the probe.
+func (b *breaker) allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.openedAt.IsZero() {
+		return nil
+	}
+	if wait := b.cooldown - time.Since(b.openedAt); wait > 0 {
+		return fmt.Errorf("%w; retry in %s", ErrCircuitOpen, wait.Round(time.Millisecond))
+	}
+	if b.probing {
+		return fmt.Errorf("%w; probe in progress", ErrCircuitOpen)
+	}
+	b.probing = true
+	return nil
+}
+
+// record reports the outcome of an allowed attempt.
+func (b *breaker) record(failed bool) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if !failed {
+		b.failures = 0
+		b.openedAt = time.Time{}
+		return
+	}
+	b.failures++
+	if !b.openedAt.IsZero() || b.failures >= b.threshold {
+		b.openedAt = time.Now()
+	}
+}
+
+// release gives back an allowed attempt that ended without telling anything
+// about the API, because the caller's context was cancelled.
+func (b *breaker) release() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
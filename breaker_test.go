@@ -0,0 +1,116 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// expireCooldown moves an open breaker to the end of its cooldown.
+func expireCooldown(b *breaker) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.openedAt = time.Now().Add(-b.cooldown)
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	var requests atomic.Int32
+	var failing atomic.Bool
+	failing.Store(true)
+	h := func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		success(w, r)
+	}
+	c := newTestClient(t, h, WithCircuitBreaker(2, time.Hour))
+	ctx := context.Background()
+	get := func() error { return c.do(ctx, http.MethodGet, "/devices", nil, nil) }
+
+	for i := 0; i < 2; i++ {
+		if err := get(); errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("attempt %d rejected before the threshold", i)
+		}
+	}
+	if err := get(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("after 2 failures: err = %v, want ErrCircuitOpen", err)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Fatalf("%d requests sent, want 2: an open breaker must not send", n)
+	}
+
+	// Half-open: a failed probe reopens the breaker.
+	expireCooldown(c.breaker)
+	if err := get(); errors.Is(err, ErrCircuitOpen) {
+		t.Fatal("probe rejected after the cooldown")
+	}
+	if err := get(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("after a failed probe: err = %v, want ErrCircuitOpen", err)
+	}
+
+	// A successful probe closes it.
+	failing.Store(false)
+	expireCooldown(c.breaker)
+	for i := 0; i < 3; i++ {
+		if err := get(); err != nil {
+			t.Fatalf("request %d after a successful probe: %v", i, err)
+		}
+	}
+	if n := requests.Load(); n != 6 {
+		t.Errorf("%d requests sent, want 6", n)
+	}
+}
+
+func TestCircuitBreakerSingleProbe(t *testing.T) {
+	b := newBreaker(1, time.Hour)
+	if err := b.allow(); err != nil {
+		t.Fatal(err)
+	}
+	b.record(true)
+	expireCooldown(b)
+	if err := b.allow(); err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	if err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("second caller during the probe: err = %v, want ErrCircuitOpen", err)
+	}
+}
+
+func TestCircuitBreakerCountsTimeouts(t *testing.T) {
+	c := newTestClient(t, slowHandler(time.Second), WithRequestTimeout(20*time.Millisecond), WithCircuitBreaker(1, time.Hour))
+	if err := c.do(context.Background(), http.MethodGet, "/devices", nil, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want a deadline error", err)
+	}
+	if err := c.do(context.Background(), http.MethodGet, "/devices", nil, nil); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("after a timed out attempt: err = %v, want ErrCircuitOpen", err)
+	}
+}
+
+func TestCircuitBreakerIgnoresCancellation(t *testing.T) {
+	c := newTestClient(t, slowHandler(time.Second), WithCircuitBreaker(1, time.Hour))
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	if err := c.do(ctx, http.MethodGet, "/devices", nil, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if err := c.breaker.allow(); err != nil {
+		t.Errorf("after a cancelled request: %v, want the breaker closed", err)
+	}
+}
+
+func TestCircuitBreakerIgnoresAPIErrors(t *testing.T) {
+	h := func(w http.ResponseWriter, r *http.Request) {
+		writeAPIError(w, statusDeviceNotFound, "device not found")
+	}
+	c := newTestClient(t, h, WithCircuitBreaker(1, time.Hour))
+	for i := 0; i < 3; i++ {
+		if err := c.do(context.Background(), http.MethodGet, "/devices", nil, nil); errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("request %d: breaker opened on an API rejection", i)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package switchbot
+
+import (
+	"fmt"
+	"time"
+)
+
+// Builder assembles a Client step by step, as an alternative to NewClient
+// with functional options, which it uses underneath.
+//
+//	c, err := switchbot.NewBuilder().
+//		Token(token).
+//		Secret(secret).
+//		Timeout(5 * time.Second).
+//		Build()
+type Builder struct {
+	token  string
+	secret string
+	opts   []Option
+}
+
+// NewBuilder returns an empty Builder.
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// Token sets the API token. It is required.
+func (b *Builder) Token(token string) *Builder {
+	b.token = token
+	return b
+}
+
+// Secret sets the API secret. It is required.
+func (b *Builder) Secret(secret string) *Builder {
+	b.secret = secret
+	return b
+}
+
+// BaseURL is WithBaseURL.
+func (b *Builder) BaseURL(baseURL string) *Builder {
+	return b.With(WithBaseURL(baseURL))
+}
+
+// Timeout is WithTimeout.
+func (b *Builder) Timeout(d time.Duration) *Builder {
+	return b.With(WithTimeout(d))
+}
+
+// RateLimit is WithRateLimit.
+func (b *Builder) RateLimit(perSecond float64, burst int) *Builder {
+	return b.With(WithRateLimit(perSecond, burst))
+}
+
+// Retry is WithRetry.
+func (b *Builder) Retry(maxRetries int, baseDelay time.Duration) *Builder {
+	return b.With(WithRetry(maxRetries, baseDelay))
+}
+
+// With adds any other option.
+func (b *Builder) With(opts ...Option) *Builder {
+	b.opts = append(b.opts, opts...)
+	return b
+}
+
+// Build checks the token and secret were set and returns the Client.
+func (b *Builder) Build() (*Client, error) {
+	if b.token == "" {
+		return nil, fmt.Errorf("%w: token is required", ErrInvalidCredentials)
+	}
+	if b.secret == "" {
+		return nil, fmt.Errorf("%w: secret is required", ErrInvalidCredentials)
+	}
+	return NewClient(b.token, b.secret, b.opts...)
+}
@@ -0,0 +1,209 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrDeviceNotFound is returned when no device or IR remote matches a
+	// name or id.
+	ErrDeviceNotFound = errors.New("device not found")
+
+	// ErrAmbiguousName is returned when a name matches more than one device.
+	ErrAmbiguousName = errors.New("device name is ambiguous")
+)
+
+// deviceCache holds the device list and its index for WithDeviceCache.
+type deviceCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	list    *deviceList
+	index   *DeviceIndex
+	fetched time.Time
+}
+
+// deviceList returns the device list, from the cache if one is configured
+// and still fresh.
+func (c *Client) deviceList(ctx context.Context) (*deviceList, error) {
+	list, _, err := c.cachedDevices(ctx)
+	return list, err
+}
+
+// cachedDevices returns the device list with its index, fetching both if
+// there is no fresh cached copy. Without WithDeviceCache every call fetches.
+func (c *Client) cachedDevices(ctx context.Context) (*deviceList, *DeviceIndex, error) {
+	if c.devices == nil {
+		list, err := c.fetchDeviceList(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		return list, newDeviceIndex(list), nil
+	}
+
+	// Holding the lock across the fetch keeps concurrent callers from all
+	// fetching at once when the cache expires
+	c.devices.mu.Lock()
+	defer c.devices.mu.Unlock()
+
+	if c.devices.list != nil && time.Since(c.devices.fetched) < c.devices.ttl {
+		return c.devices.list, c.devices.index, nil
+	}
+
+	list, err := c.fetchDeviceList(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	c.devices.list = list
+	c.devices.index = newDeviceIndex(list)
+	c.devices.fetched = time.Now()
+	return list, c.devices.index, nil
+}
+
+// cachedTypeOf returns the type of a device from the cached device list, or
+// "" if there is no fresh cached list or the device isn't in it. It never
+// fetches.
+func (c *Client) cachedTypeOf(id string) DeviceType {
+	if c.devices == nil {
+		return ""
+	}
+	c.devices.mu.Lock()
+	defer c.devices.mu.Unlock()
+	if c.devices.list == nil || time.Since(c.devices.fetched) >= c.devices.ttl {
+		return ""
+	}
+	return c.devices.list.typeOf(id)
+}
+
+func (c *Client) fetchDeviceList(ctx context.Context) (*deviceList, error) {
+	var list deviceList
+	if err := c.do(ctx, http.MethodGet, "/devices", nil, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// DeviceIndex maps device and IR remote ids and names to ids for constant
+// time lookups. Names are matched case-insensitively.
+type DeviceIndex struct {
+	ids   map[string]bool
+	names map[string][]string
+}
+
+func newDeviceIndex(list *deviceList) *DeviceIndex {
+	idx := &DeviceIndex{
+		ids:   make(map[string]bool),
+		names: make(map[string][]string),
+	}
+	add := func(id, name string) {
+		idx.ids[id] = true
+		key := strings.ToLower(name)
+		idx.names[key] = append(idx.names[key], id)
+	}
+	for _, d := range list.DeviceList {
+		add(d.DeviceID, d.DeviceName)
+	}
+	for _, r := range list.InfraredRemoteList {
+		add(r.DeviceID, r.DeviceName)
+	}
+	return idx
+}
+
+// Resolve returns the id for nameOrID, which may be a device id or a device
+// name. Ids take precedence over names. It returns ErrDeviceNotFound if
+// nothing matches and ErrAmbiguousName if the name is shared by several
+// devices.
+func (idx *DeviceIndex) Resolve(nameOrID string) (string, error) {
+	if idx.ids[nameOrID] {
+		return nameOrID, nil
+	}
+
+	ids := idx.names[strings.ToLower(nameOrID)]
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("%w: %q", ErrDeviceNotFound, nameOrID)
+	case 1:
+		return ids[0], nil
+	default:
+		return "", fmt.Errorf("%w: %q matches %s", ErrAmbiguousName, nameOrID, strings.Join(ids, ", "))
+	}
+}
+
+// DeviceIndex returns the index of the current device list. With
+// WithDeviceCache it is rebuilt only when the cached list is refreshed.
+func (c *Client) DeviceIndex(ctx context.Context) (*DeviceIndex, error) {
+	_, idx, err := c.cachedDevices(ctx)
+	return idx, err
+}
+
+// ResolveID returns the canonical id for a device or IR remote given by name
+// or id. See DeviceIndex.Resolve.
+func (c *Client) ResolveID(ctx context.Context, nameOrID string) (string, error) {
+	idx, err := c.DeviceIndex(ctx)
+	if err != nil {
+		return "", err
+	}
+	return idx.Resolve(nameOrID)
+}
+
+// NameConflict is a device name shared by several devices or IR remotes.
+type NameConflict struct {
+	// Name is the name as first listed; names are compared
+	// case-insensitively.
+	Name      string
+	DeviceIDs []string
+}
+
+// CheckNameUniqueness looks for device and IR remote names that name
+// lookups such as ResolveID would reject with ErrAmbiguousName, so they can
+// be renamed before a call fails. It returns the conflicts in device list
+// order with an error wrapping ErrAmbiguousName if there are any, and logs
+// each one as a warning when WithLogger is set.
+func (c *Client) CheckNameUniqueness(ctx context.Context) ([]NameConflict, error) {
+	list, err := c.deviceList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var order []string
+	byName := make(map[string]*NameConflict)
+	add := func(id, name string) {
+		key := strings.ToLower(name)
+		nc, ok := byName[key]
+		if !ok {
+			nc = &NameConflict{Name: name}
+			byName[key] = nc
+			order = append(order, key)
+		}
+		nc.DeviceIDs = append(nc.DeviceIDs, id)
+	}
+	for _, d := range list.DeviceList {
+		add(d.DeviceID, d.DeviceName)
+	}
+	for _, r := range list.InfraredRemoteList {
+		add(r.DeviceID, r.DeviceName)
+	}
+
+	var conflicts []NameConflict
+	var names []string
+	for _, key := range order {
+		nc := byName[key]
+		if len(nc.DeviceIDs) < 2 {
+			continue
+		}
+		conflicts = append(conflicts, *nc)
+		names = append(names, fmt.Sprintf("%q", nc.Name))
+		if c.logger != nil {
+			c.logger.WarnContext(ctx, "switchbot duplicate device name", "name", nc.Name, "device_ids", nc.DeviceIDs)
+		}
+	}
+	if len(conflicts) > 0 {
+		return conflicts, fmt.Errorf("%w: %s", ErrAmbiguousName, strings.Join(names, ", "))
+	}
+	return nil, nil
+}
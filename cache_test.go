@@ -0,0 +1,85 @@
+package switchbot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckNameUniqueness(t *testing.T) {
+	devices := []Device{
+		{DeviceID: "BOT01", DeviceName: "Kitchen"},
+		{DeviceID: "PLUG01", DeviceName: "Desk"},
+		{DeviceID: "BOT02", DeviceName: "kitchen"},
+		{DeviceID: "PLUG02", DeviceName: "Lamp"},
+	}
+	remotes := []InfraredRemote{
+		{DeviceID: "IR01", DeviceName: "Lamp"},
+		{DeviceID: "IR02", DeviceName: "TV"},
+	}
+	var logs bytes.Buffer
+	c, _ := newDeviceClient(t, devices, remotes, WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+
+	conflicts, err := c.CheckNameUniqueness(context.Background())
+	if !errors.Is(err, ErrAmbiguousName) {
+		t.Fatalf("err = %v, want ErrAmbiguousName", err)
+	}
+	want := []NameConflict{
+		{Name: "Kitchen", DeviceIDs: []string{"BOT01", "BOT02"}},
+		{Name: "Lamp", DeviceIDs: []string{"PLUG02", "IR01"}},
+	}
+	if !reflect.DeepEqual(conflicts, want) {
+		t.Errorf("conflicts = %+v, want %+v", conflicts, want)
+	}
+	if n := strings.Count(logs.String(), "duplicate device name"); n != 2 {
+		t.Errorf("logged %d warnings, want 2:\n%s", n, logs.String())
+	}
+}
+
+func TestCheckNameUniquenessNone(t *testing.T) {
+	c, _ := newDeviceClient(t, []Device{{DeviceID: "BOT01", DeviceName: "Kitchen"}}, []InfraredRemote{{DeviceID: "IR01", DeviceName: "TV"}})
+	conflicts, err := c.CheckNameUniqueness(context.Background())
+	if err != nil || conflicts != nil {
+		t.Errorf("CheckNameUniqueness = %v, %v, want no conflicts", conflicts, err)
+	}
+}
+
+func TestResolveID(t *testing.T) {
+	devices := []Device{
+		{DeviceID: "BOT01", DeviceName: "Kettle"},
+		{DeviceID: "PLUG01", DeviceName: "Lamp"},
+		{DeviceID: "PLUG02", DeviceName: "Lamp"},
+	}
+	remotes := []InfraredRemote{{DeviceID: "TV01", DeviceName: "Living Room TV"}}
+	c, _ := newDeviceClient(t, devices, remotes)
+	ctx := context.Background()
+
+	tests := []struct {
+		nameOrID, want string
+	}{
+		{"BOT01", "BOT01"},
+		{"Kettle", "BOT01"},
+		{"KETTLE", "BOT01"},
+		{"PLUG02", "PLUG02"},
+		{"living room tv", "TV01"},
+	}
+	for _, tt := range tests {
+		if got, err := c.ResolveID(ctx, tt.nameOrID); err != nil || got != tt.want {
+			t.Errorf("ResolveID(%q) = %q, %v, want %q", tt.nameOrID, got, err, tt.want)
+		}
+	}
+
+	_, err := c.ResolveID(ctx, "Lamp")
+	if !errors.Is(err, ErrAmbiguousName) {
+		t.Errorf("ResolveID(Lamp) err = %v, want ErrAmbiguousName", err)
+	} else if !strings.Contains(err.Error(), "PLUG01") || !strings.Contains(err.Error(), "PLUG02") {
+		t.Errorf("ambiguity error %q doesn't name both devices", err)
+	}
+	if _, err := c.ResolveID(ctx, "Toaster"); !errors.Is(err, ErrDeviceNotFound) {
+		t.Errorf("ResolveID(Toaster) err = %v, want ErrDeviceNotFound", err)
+	}
+}
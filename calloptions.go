@@ -0,0 +1,65 @@
+package switchbot
+
+import (
+	"context"
+	"time"
+)
+
+// CallOption overrides a client setting for a single call, e.g.
+//
+//	c.SendCommand(ctx, id, cmd, WithCallTimeout(2*time.Second), WithCallRetries(0))
+//
+// Settings not overridden keep the client's value.
+type CallOption func(*callConfig)
+
+// callConfig is the set of per-call overrides. A nil field keeps the
+// client's setting.
+type callConfig struct {
+	timeout time.Duration
+	retries *int
+}
+
+// WithCallTimeout bounds the call by d, including retries and, for
+// commands, any WithCommandCooldown wait. It replaces the client's request
+// timeout and any WithCommandTimeouts entry, but can't extend a deadline
+// already on the context.
+func WithCallTimeout(d time.Duration) CallOption {
+	return func(cfg *callConfig) {
+		cfg.timeout = d
+	}
+}
+
+// WithCallRetries retries the call at most n times instead of the number set
+// by WithRetry; 0 disables retries, for commands that mustn't be repeated.
+func WithCallRetries(n int) CallOption {
+	return func(cfg *callConfig) {
+		cfg.retries = &n
+	}
+}
+
+type callConfigKey struct{}
+
+// withCallOptions applies opts to ctx: the timeout as a deadline and the rest
+// as a context value read by send.
+func withCallOptions(ctx context.Context, opts []CallOption) (context.Context, context.CancelFunc) {
+	if len(opts) == 0 {
+		return ctx, func() {}
+	}
+	cfg := &callConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	ctx = context.WithValue(ctx, callConfigKey{}, cfg)
+	if cfg.timeout > 0 {
+		return context.WithTimeout(ctx, cfg.timeout)
+	}
+	return ctx, func() {}
+}
+
+// maxRetriesFor returns the retry limit for a request made with ctx.
+func (c *Client) maxRetriesFor(ctx context.Context) int {
+	if cfg, ok := ctx.Value(callConfigKey{}).(*callConfig); ok && cfg.retries != nil {
+		return *cfg.retries
+	}
+	return c.maxRetries
+}
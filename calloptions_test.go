@@ -0,0 +1,76 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithCallRetries(t *testing.T) {
+	var attempts atomic.Int32
+	h := func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	c := newTestClient(t, h, WithRetry(3, 0))
+	ctx := context.Background()
+
+	if err := c.SendCommand(ctx, "BOT01", Command{Command: "press"}, WithCallRetries(0)); err == nil {
+		t.Fatal("expected an error")
+	}
+	if n := attempts.Swap(0); n != 1 {
+		t.Errorf("WithCallRetries(0): %d attempts, want 1", n)
+	}
+
+	// The override applies to that call only
+	if err := c.SendCommand(ctx, "BOT01", Command{Command: "press"}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if n := attempts.Load(); n != 4 {
+		t.Errorf("client default: %d attempts, want 4", n)
+	}
+}
+
+func TestWithCallTimeout(t *testing.T) {
+	slow := slowHandler(time.Second)
+	h := func(w http.ResponseWriter, r *http.Request) {
+		// The server only notices the client going away once the body
+		// has been read
+		io.Copy(io.Discard, r.Body)
+		slow(w, r)
+	}
+	c := newTestClient(t, h, WithRequestTimeout(time.Hour))
+
+	start := time.Now()
+	err := c.SendCommand(context.Background(), "BOT01", Command{Command: "press"}, WithCallTimeout(20*time.Millisecond))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want a deadline error", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("call took %v, want it cut off by the 20ms call timeout", elapsed)
+	}
+}
+
+func TestWithCallTimeoutBoundsCooldown(t *testing.T) {
+	c, srv := newCommandClient(t, WithCommandCooldown(time.Hour, CooldownWait))
+	ctx := context.Background()
+	if err := c.SendCommand(ctx, "BOT01", Command{Command: "press"}); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	err := c.SendCommand(ctx, "BOT01", Command{Command: "press"}, WithCallTimeout(20*time.Millisecond))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want the cooldown wait cut off by the call timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("call took %v, want it bounded by the call timeout", elapsed)
+	}
+	if n := len(srv.commands()); n != 1 {
+		t.Errorf("sent %d commands, want 1", n)
+	}
+}
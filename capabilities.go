@@ -0,0 +1,108 @@
+package switchbot
+
+import "slices"
+
+// Capability is a command a device type accepts, named as sent to the API.
+type Capability string
+
+const (
+	CapabilityTurnOn              Capability = "turnOn"
+	CapabilityTurnOff             Capability = "turnOff"
+	CapabilityPress               Capability = "press"
+	CapabilitySetPosition         Capability = "setPosition"
+	CapabilityFullyOpen           Capability = "fullyOpen"
+	CapabilityCloseUp             Capability = "closeUp"
+	CapabilityCloseDown           Capability = "closeDown"
+	CapabilityLock                Capability = "lock"
+	CapabilityUnlock              Capability = "unlock"
+	CapabilitySetBrightness       Capability = "setBrightness"
+	CapabilitySetColor            Capability = "setColor"
+	CapabilitySetColorTemperature Capability = "setColorTemperature"
+	CapabilitySetNightLight       Capability = "setNightLight"
+	CapabilitySetMode             Capability = "setMode"
+	CapabilitySetChildLock        Capability = "setChildLock"
+	CapabilitySetAll              Capability = "setAll"
+	CapabilitySetWindMode         Capability = "setWindMode"
+	CapabilitySetWindSpeed        Capability = "setWindSpeed"
+	CapabilitySetNightLightMode   Capability = "setNightLightMode"
+	CapabilityVolumeAdd           Capability = "volumeAdd"
+	CapabilityVolumeSub           Capability = "volumeSub"
+	CapabilityChannelAdd          Capability = "channelAdd"
+	CapabilityChannelSub          Capability = "channelSub"
+	CapabilitySetChannel          Capability = "SetChannel"
+	CapabilitySetMute             Capability = "setMute"
+)
+
+var (
+	powerCapabilities        = []Capability{CapabilityTurnOn, CapabilityTurnOff}
+	airPurifierCapabilities  = []Capability{CapabilityTurnOn, CapabilityTurnOff, CapabilitySetMode, CapabilitySetChildLock}
+	colorLightCapabilities   = []Capability{CapabilityTurnOn, CapabilityTurnOff, CapabilitySetBrightness, CapabilitySetColor, CapabilitySetColorTemperature}
+	ceilingLightCapabilities = []Capability{CapabilityTurnOn, CapabilityTurnOff, CapabilitySetBrightness, CapabilitySetColorTemperature, CapabilitySetNightLight}
+	curtainCapabilities      = []Capability{CapabilityTurnOn, CapabilityTurnOff, CapabilitySetPosition}
+	lockCapabilities         = []Capability{CapabilityLock, CapabilityUnlock}
+
+	// infraredCapabilities are the commands of the IR helpers across every
+	// remote category: setAll for air conditioners, the rest for TVs, set
+	// top boxes, DVD players and speakers.
+	infraredCapabilities = []Capability{
+		CapabilityTurnOn, CapabilityTurnOff, CapabilitySetAll,
+		CapabilityVolumeAdd, CapabilityVolumeSub, CapabilityChannelAdd, CapabilityChannelSub,
+		CapabilitySetChannel, CapabilitySetMute,
+	}
+)
+
+// capabilities is the static table behind Capabilities.
+var capabilities = map[DeviceType][]Capability{
+	DeviceTypeAirPurifierVOC:       airPurifierCapabilities,
+	DeviceTypeAirPurifierTableVOC:  airPurifierCapabilities,
+	DeviceTypeAirPurifierPM25:      airPurifierCapabilities,
+	DeviceTypeAirPurifierTablePM25: airPurifierCapabilities,
+	DeviceTypeBlindTilt:            {CapabilitySetPosition, CapabilityFullyOpen, CapabilityCloseUp, CapabilityCloseDown},
+	DeviceTypeBot:                  {CapabilityPress, CapabilityTurnOn, CapabilityTurnOff},
+	DeviceTypeCeilingLight:         ceilingLightCapabilities,
+	DeviceTypeCeilingLightPro:      ceilingLightCapabilities,
+	DeviceTypeCirculatorFan:        {CapabilityTurnOn, CapabilityTurnOff, CapabilitySetWindMode, CapabilitySetWindSpeed, CapabilitySetNightLightMode},
+	DeviceTypeColorBulb:            colorLightCapabilities,
+	DeviceTypeCurtain:              curtainCapabilities,
+	DeviceTypeCurtain3:             curtainCapabilities,
+	DeviceTypeFloorLamp:            colorLightCapabilities,
+	DeviceTypeHumidifier:           {CapabilityTurnOn, CapabilityTurnOff, CapabilitySetMode},
+	DeviceTypeInfraredRemote:       infraredCapabilities,
+	DeviceTypeLock:                 lockCapabilities,
+	DeviceTypeLockPro:              lockCapabilities,
+	DeviceTypePlug:                 powerCapabilities,
+	DeviceTypePlugMiniUS:           powerCapabilities,
+	DeviceTypePlugMiniJP:           powerCapabilities,
+	DeviceTypeStripLight:           {CapabilityTurnOn, CapabilityTurnOff, CapabilitySetBrightness, CapabilitySetColor},
+	DeviceTypeStripLight3:          colorLightCapabilities,
+}
+
+// Capabilities returns the commands a device type accepts, so a UI can offer
+// the right controls without trial and error. Sensors, hubs and unknown
+// types have none. For IR remotes the commands of every IR helper are
+// listed, since the type doesn't tell the remote's category; which ones a
+// remote honours depends on its category and what was learned, and custom
+// buttons aren't listed (see SendCustomIRButton). The slice is a fresh copy
+// the caller may modify.
+func Capabilities(t DeviceType) []Capability {
+	return slices.Clone(capabilities[t])
+}
+
+// Supports reports whether device type t accepts the command.
+func Supports(t DeviceType, c Capability) bool {
+	return slices.Contains(capabilities[t], c)
+}
+
+// noStatusTypes lists the device types SwitchBot reports no status for; the
+// status endpoint answers statusCode 151 for them.
+var noStatusTypes = map[DeviceType]bool{
+	DeviceTypeInfraredRemote: true,
+}
+
+// SupportsStatus reports whether the status of devices of type t can be
+// read. It is true for types the package doesn't model, since SwitchBot
+// reports a status for most physical devices; for those DeviceStatus returns
+// an *UnknownStatus.
+func SupportsStatus(t DeviceType) bool {
+	return t != "" && !noStatusTypes[t]
+}
@@ -0,0 +1,157 @@
+package switchbot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// typedServer fakes one device of type t: status reads report it powered
+// off in switch mode, and commands are recorded.
+type typedServer struct {
+	t    DeviceType
+	mu   sync.Mutex
+	sent []Command
+}
+
+func (s *typedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, apiVersion+"/devices/")
+	if id, ok := strings.CutSuffix(path, "/status"); ok {
+		writeEnvelope(w, statusSuccess, map[string]any{"deviceId": id, "deviceType": s.t, "power": PowerOff, "deviceMode": BotModeSwitch})
+		return
+	}
+	if strings.HasSuffix(path, "/commands") {
+		var cmd Command
+		json.NewDecoder(r.Body).Decode(&cmd)
+		s.mu.Lock()
+		s.sent = append(s.sent, cmd)
+		s.mu.Unlock()
+	}
+	success(w, r)
+}
+
+func TestCommandHelpersListedInCapabilities(t *testing.T) {
+	type helper func(c *Client, ctx context.Context, id string) error
+	helpers := []struct {
+		name string
+		t    DeviceType
+		call helper
+	}{
+		{"BotPress", DeviceTypeBot, (*Client).BotPress},
+		{"BotTurnOn", DeviceTypeBot, (*Client).BotTurnOn},
+		{"BotTurnOff", DeviceTypeBot, (*Client).BotTurnOff},
+		{"BotPressAndConfirm", DeviceTypeBot, func(c *Client, ctx context.Context, id string) error {
+			return c.BotPressAndConfirm(ctx, id, time.Second)
+		}},
+		{"Toggle bot", DeviceTypeBot, (*Client).Toggle},
+		{"Toggle plug", DeviceTypePlugMiniUS, (*Client).Toggle},
+		{"Toggle light", DeviceTypeColorBulb, (*Client).Toggle},
+		{"LightTurnOn", DeviceTypeColorBulb, (*Client).LightTurnOn},
+		{"LightTurnOff", DeviceTypeColorBulb, (*Client).LightTurnOff},
+		{"LightSetBrightness", DeviceTypeColorBulb, func(c *Client, ctx context.Context, id string) error {
+			return c.LightSetBrightness(ctx, id, 50)
+		}},
+		{"LightTurnOnAt", DeviceTypeStripLight, func(c *Client, ctx context.Context, id string) error {
+			return c.LightTurnOnAt(ctx, id, 50)
+		}},
+		{"LightSetColor", DeviceTypeStripLight, func(c *Client, ctx context.Context, id string) error {
+			return c.LightSetColor(ctx, id, 255, 128, 0)
+		}},
+		{"LightSetColorTemperature", DeviceTypeCeilingLight, func(c *Client, ctx context.Context, id string) error {
+			return c.LightSetColorTemperature(ctx, id, 4000)
+		}},
+		{"LightSetNightlight", DeviceTypeCeilingLightPro, func(c *Client, ctx context.Context, id string) error {
+			return c.LightSetNightlight(ctx, id, 10)
+		}},
+		{"CurtainSetPosition", DeviceTypeCurtain3, func(c *Client, ctx context.Context, id string) error {
+			return c.CurtainSetPosition(ctx, id, 50, CurtainModeDefault)
+		}},
+		{"CurtainOpen", DeviceTypeCurtain, (*Client).CurtainOpen},
+		{"CurtainClose", DeviceTypeCurtain, (*Client).CurtainClose},
+		{"BlindTiltSetPosition", DeviceTypeBlindTilt, func(c *Client, ctx context.Context, id string) error {
+			return c.BlindTiltSetPosition(ctx, id, TiltUp, 50)
+		}},
+		{"BlindTiltOpen", DeviceTypeBlindTilt, (*Client).BlindTiltOpen},
+		{"BlindTiltClose up", DeviceTypeBlindTilt, func(c *Client, ctx context.Context, id string) error {
+			return c.BlindTiltClose(ctx, id, TiltUp)
+		}},
+		{"BlindTiltClose down", DeviceTypeBlindTilt, func(c *Client, ctx context.Context, id string) error {
+			return c.BlindTiltClose(ctx, id, TiltDown)
+		}},
+		{"Lock", DeviceTypeLockPro, (*Client).Lock},
+		{"Unlock", DeviceTypeLock, (*Client).Unlock},
+		{"AirPurifierTurnOn", DeviceTypeAirPurifierVOC, (*Client).AirPurifierTurnOn},
+		{"AirPurifierTurnOff", DeviceTypeAirPurifierPM25, (*Client).AirPurifierTurnOff},
+		{"AirPurifierSetMode", DeviceTypeAirPurifierTableVOC, func(c *Client, ctx context.Context, id string) error {
+			return c.AirPurifierSetMode(ctx, id, AirPurifierModeNormal, 2)
+		}},
+		{"AirPurifierSetChildLock", DeviceTypeAirPurifierTablePM25, func(c *Client, ctx context.Context, id string) error {
+			return c.AirPurifierSetChildLock(ctx, id, true)
+		}},
+		{"FanTurnOn", DeviceTypeCirculatorFan, (*Client).FanTurnOn},
+		{"FanTurnOff", DeviceTypeCirculatorFan, (*Client).FanTurnOff},
+		{"FanSetWindMode", DeviceTypeCirculatorFan, func(c *Client, ctx context.Context, id string) error {
+			return c.FanSetWindMode(ctx, id, FanModeNatural)
+		}},
+		{"FanSetWindSpeed", DeviceTypeCirculatorFan, func(c *Client, ctx context.Context, id string) error {
+			return c.FanSetWindSpeed(ctx, id, 50)
+		}},
+		{"FanSetNightLightMode", DeviceTypeCirculatorFan, func(c *Client, ctx context.Context, id string) error {
+			return c.FanSetNightLightMode(ctx, id, FanNightLight1)
+		}},
+		{"FanSetAll", DeviceTypeCirculatorFan, func(c *Client, ctx context.Context, id string) error {
+			return c.FanSetAll(ctx, id, FanSettings{Power: true, Mode: FanModeDirect, Speed: 30, NightLight: FanNightLightOff})
+		}},
+		{"ACSetAll", DeviceTypeInfraredRemote, func(c *Client, ctx context.Context, id string) error {
+			return c.ACSetAll(ctx, id, ACSettings{Temperature: 24, Mode: ACModeCool, Fan: ACFanAuto, Power: true})
+		}},
+		{"TVVolumeUp", DeviceTypeInfraredRemote, (*Client).TVVolumeUp},
+		{"TVVolumeDown", DeviceTypeInfraredRemote, (*Client).TVVolumeDown},
+		{"TVChannelUp", DeviceTypeInfraredRemote, (*Client).TVChannelUp},
+		{"TVChannelDown", DeviceTypeInfraredRemote, (*Client).TVChannelDown},
+		{"TVSetChannel", DeviceTypeInfraredRemote, func(c *Client, ctx context.Context, id string) error {
+			return c.TVSetChannel(ctx, id, 7)
+		}},
+		{"TVMute", DeviceTypeInfraredRemote, (*Client).TVMute},
+	}
+
+	for _, h := range helpers {
+		srv := &typedServer{t: h.t}
+		c := newTestClient(t, srv.ServeHTTP, WithPollInterval(time.Millisecond))
+		if err := h.call(c, context.Background(), "DEV01"); err != nil {
+			t.Errorf("%s: %v", h.name, err)
+			continue
+		}
+		if len(srv.sent) == 0 {
+			t.Errorf("%s sent no command", h.name)
+		}
+		for _, cmd := range srv.sent {
+			if !Supports(h.t, Capability(cmd.Command)) {
+				t.Errorf("%s sends %q, which Capabilities(%s) doesn't list", h.name, cmd.Command, h.t)
+			}
+		}
+	}
+}
+
+func TestDefaultOffPolicyListedInCapabilities(t *testing.T) {
+	for typ, cmd := range DefaultOffPolicy() {
+		if !Supports(typ, Capability(cmd.Command)) {
+			t.Errorf("DefaultOffPolicy sends %q to %s, which Capabilities doesn't list", cmd.Command, typ)
+		}
+	}
+}
+
+func TestCapabilitiesCopy(t *testing.T) {
+	caps := Capabilities(DeviceTypePlug)
+	caps[0] = "broken"
+	if Capabilities(DeviceTypePlug)[0] == "broken" {
+		t.Error("Capabilities returned the shared table")
+	}
+	if caps := Capabilities(DeviceTypeMeter); len(caps) != 0 {
+		t.Errorf("Capabilities(Meter) = %v, want none for a sensor", caps)
+	}
+}
@@ -0,0 +1,403 @@
+// Package switchbot is a client for the SwitchBot cloud API (v1.1).
+package switchbot
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultBaseURL is the SwitchBot cloud API host.
+	DefaultBaseURL = "https://api.switch-bot.com"
+
+	// DefaultTimeout is the HTTP client timeout used when none is configured.
+	DefaultTimeout = 10 * time.Second
+
+	// DefaultMaxResponseBytes bounds the size of a response body.
+	DefaultMaxResponseBytes = 5 << 20
+
+	// DefaultConcurrency bounds the goroutines used by batch helpers such as
+	// SnapshotAll.
+	DefaultConcurrency = 4
+
+	// RegionGlobal is the default region, served by DefaultBaseURL.
+	RegionGlobal = "global"
+
+	apiVersion = "/v1.1"
+
+	// statusSuccess is the envelope statusCode SwitchBot returns on success.
+	statusSuccess = 100
+)
+
+// regionHosts maps a WithRegion region to its API host. SwitchBot currently
+// has one global host; the empty region is the default.
+var regionHosts = map[string]string{
+	"":           DefaultBaseURL,
+	RegionGlobal: DefaultBaseURL,
+}
+
+// Client talks to the SwitchBot API using a token and secret from the
+// SwitchBot app. A Client is safe for concurrent use.
+type Client struct {
+	token       string
+	secret      string
+	baseURL     string
+	region      string
+	httpClient  *http.Client
+	httpTimeout time.Duration
+
+	limiter        *rateLimiter
+	concurrency    int
+	requestTimeout time.Duration
+	maxRetries     int
+	retryBaseDelay time.Duration
+	metrics        Metrics
+	etags          *etagCache
+	pollInterval   time.Duration
+	strict         bool
+	devices        *deviceCache
+	scenes         *sceneCache
+	cooldown       *cooldown
+	maxBodyBytes   int64
+	logger         *slog.Logger
+	compression    bool
+	acStates       acStateStore
+	skewRetry      bool
+	clock          clock
+	transport      *transportConfig
+	nonces         NonceGenerator
+
+	commandTimeouts map[DeviceType]time.Duration
+	temperatureUnit TemperatureUnit
+	breaker         *breaker
+	recorder        *trafficRecorder
+	jitter          Jitter
+	results         *resultStore
+	schedules       scheduler
+	hubCheck        bool
+	commandLocks    commandLocks
+	sceneHistory    *sceneHistory
+	trafficIndent   string
+	annotations     annotationStore
+}
+
+// NewClient returns a Client for the given token and secret, configured by
+// the supplied options. The credentials are checked with ValidateCredentials
+// first.
+func NewClient(token, secret string, opts ...Option) (*Client, error) {
+	if err := ValidateCredentials(token, secret); err != nil {
+		return nil, err
+	}
+
+	defaultHTTPClient := &http.Client{Timeout: DefaultTimeout, CheckRedirect: StripCredentialsOnRedirect}
+	c := &Client{
+		token:        token,
+		secret:       secret,
+		httpClient:   defaultHTTPClient,
+		httpTimeout:  DefaultTimeout,
+		concurrency:  DefaultConcurrency,
+		metrics:      noopMetrics{},
+		pollInterval: DefaultPollInterval,
+		maxBodyBytes: DefaultMaxResponseBytes,
+		nonces:       randomUUID{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	// An explicit base URL wins over the region, whatever the option order
+	if c.baseURL == "" {
+		host, ok := regionHosts[c.region]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown region %q", ErrInvalidParameter, c.region)
+		}
+		c.baseURL = host
+	}
+
+	// The timeout and transport tuning only apply to the client's own HTTP
+	// client; one passed to WithHTTPClient is never modified
+	if c.httpClient == defaultHTTPClient {
+		c.httpClient.Timeout = c.httpTimeout
+		if c.transport != nil {
+			c.httpClient.Transport = c.transport.apply()
+		}
+	}
+	if c.recorder != nil && c.trafficIndent != "" {
+		c.recorder.enc.SetIndent("", c.trafficIndent)
+	}
+
+	return c, nil
+}
+
+// envelope is the wrapper SwitchBot puts around every response body.
+// StatusCode is nil when absent, as in responses seen from some older
+// accounts.
+type envelope struct {
+	StatusCode *int            `json:"statusCode"`
+	Message    string          `json:"message"`
+	Body       json.RawMessage `json:"body"`
+}
+
+// succeeded reports whether the envelope is a success: statusCode 100, or
+// no statusCode but a body.
+func (e *envelope) succeeded() bool {
+	if e.StatusCode == nil {
+		return len(e.Body) > 0 && string(e.Body) != "null"
+	}
+	return *e.StatusCode == statusSuccess
+}
+
+// apiRequest describes one API call made through send.
+type apiRequest struct {
+	method  string
+	path    string
+	payload any // encoded as the JSON request body unless nil
+	out     any // receives the envelope body unless nil
+
+	// header holds extra request headers, e.g. If-None-Match.
+	header http.Header
+
+	// Filled in from the final response.
+	respHeader  http.Header
+	respMessage string // envelope message, e.g. "success"
+	notModified bool
+
+	resigned bool // set once a 401 has been retried with a corrected clock
+}
+
+// do signs and sends a request to the API, checks the response envelope and
+// decodes its body into out. A nil payload sends no request body and a nil out
+// discards the response body.
+func (c *Client) do(ctx context.Context, method, path string, payload, out any) error {
+	return c.send(ctx, &apiRequest{method: method, path: path, payload: payload, out: out})
+}
+
+// send performs r, retrying failed attempts as configured by WithRetry.
+func (c *Client) send(ctx context.Context, r *apiRequest) error {
+	ctx, cancel := c.withRequestTimeout(ctx)
+	defer cancel()
+
+	maxRetries := c.maxRetriesFor(ctx)
+	var reqBody []byte
+	if r.payload != nil {
+		b, err := json.Marshal(r.payload)
+		if err != nil {
+			return fmt.Errorf("error encoding request body: %w", err)
+		}
+		reqBody = b
+	}
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			if ro, ok := c.metrics.(RetryObserver); ok {
+				ro.ObserveRetry(r.method, metricPath(r.path), attempt)
+			}
+			if err := sleep(ctx, c.backoff(attempt)); err != nil {
+				return err
+			}
+		}
+
+		if c.limiter != nil {
+			if err := c.limiter.Wait(ctx); err != nil {
+				return err
+			}
+		}
+
+		retry, err := c.guardedAttempt(ctx, r, reqBody)
+		if c.skewRetry && !r.resigned && isUnauthorized(err) && c.clock.correct(r.respHeader) {
+			// One more try, signed with SwitchBot's time. It doesn't count
+			// as a retry and is never repeated, so genuinely bad
+			// credentials still fail fast.
+			r.resigned = true
+			if c.limiter != nil {
+				if err := c.limiter.Wait(ctx); err != nil {
+					return err
+				}
+			}
+			retry, err = c.guardedAttempt(ctx, r, reqBody)
+		}
+		if err == nil || !retry || attempt >= maxRetries {
+			return err
+		}
+	}
+}
+
+// guardedAttempt is attempt behind the circuit breaker, if one is
+// configured. A rejected attempt isn't retried.
+func (c *Client) guardedAttempt(ctx context.Context, r *apiRequest, reqBody []byte) (bool, error) {
+	if err := c.breaker.allow(); err != nil {
+		return false, err
+	}
+	retry, err := c.attempt(ctx, r, reqBody)
+	switch {
+	case err != nil && errors.Is(ctx.Err(), context.Canceled):
+		// The caller gave up; the attempt tells nothing about the API.
+		c.breaker.release()
+	case err != nil && ctx.Err() != nil:
+		// The API didn't answer before the deadline.
+		c.breaker.record(true)
+	default:
+		c.breaker.record(retry)
+	}
+	return retry, err
+}
+
+// attempt makes a single HTTP round trip. It reports whether a failure is
+// worth retrying: transport errors, 429s and 5xx responses are.
+func (c *Client) attempt(ctx context.Context, r *apiRequest, reqBody []byte) (bool, error) {
+	var body io.Reader
+	if reqBody != nil {
+		body = bytes.NewReader(reqBody)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, r.method, c.baseURL+apiVersion+r.path, body)
+	if err != nil {
+		return false, fmt.Errorf("error creating request: %w", err)
+	}
+	for key, values := range r.header {
+		req.Header[key] = values
+	}
+	if c.compression {
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+
+	// Sign every attempt with a fresh nonce and timestamp
+	nonce := newNonce(c.nonces, CorrelationID(ctx))
+	headers, err := createHeaders(c.token, c.secret, nonce, c.clock.now())
+	if err != nil {
+		return false, fmt.Errorf("error creating headers: %w", err)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.observe(ctx, r, nonce, 0, time.Since(start))
+		c.record(req, reqBody, nil, nil, start, err)
+		return ctx.Err() == nil, fmt.Errorf("error executing HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var reader io.Reader = resp.Body
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			c.observe(ctx, r, nonce, resp.StatusCode, time.Since(start))
+			c.record(req, reqBody, resp, nil, start, err)
+			return false, fmt.Errorf("error decompressing response body: %w", err)
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	// Read one byte past the limit to tell a body of exactly the limit from
+	// a longer one. The limit applies after decompression.
+	respBody, err := io.ReadAll(io.LimitReader(reader, c.maxBodyBytes+1))
+	c.observe(ctx, r, nonce, resp.StatusCode, time.Since(start))
+	c.record(req, reqBody, resp, respBody, start, err)
+	if err != nil {
+		return ctx.Err() == nil, fmt.Errorf("error reading response body: %w", err)
+	}
+	if int64(len(respBody)) > c.maxBodyBytes {
+		return false, fmt.Errorf("%w: limit is %d bytes", ErrResponseTooLarge, c.maxBodyBytes)
+	}
+
+	r.respHeader = resp.Header
+	if resp.StatusCode == http.StatusNotModified && r.header.Get("If-None-Match") != "" {
+		r.notModified = true
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return retry, &HTTPError{StatusCode: resp.StatusCode}
+	}
+
+	if !looksLikeJSON(resp.Header.Get("Content-Type"), respBody) {
+		return false, fmt.Errorf("%w (Content-Type %q): %s", ErrNotJSON, resp.Header.Get("Content-Type"), c.snippet(respBody))
+	}
+
+	var env envelope
+	if err := json.Unmarshal(respBody, &env); err != nil {
+		return false, fmt.Errorf("error decoding response: %w", err)
+	}
+	if !env.succeeded() {
+		var code int
+		if env.StatusCode != nil {
+			code = *env.StatusCode
+		}
+		return false, &APIError{StatusCode: code, Message: env.Message}
+	}
+	r.respMessage = env.Message
+
+	if r.out == nil || len(env.Body) == 0 {
+		return false, nil
+	}
+	if err := c.unmarshal(env.Body, r.out); err != nil {
+		return false, fmt.Errorf("error decoding response body: %w", err)
+	}
+
+	return false, nil
+}
+
+// observe reports a finished attempt to the metrics and, at debug level, the
+// logger. The log line carries the nonce and any correlation id so it can be
+// matched with SwitchBot's side of the request.
+func (c *Client) observe(ctx context.Context, r *apiRequest, nonce string, status int, d time.Duration) {
+	c.metrics.ObserveRequest(r.method, metricPath(r.path), status, d)
+	if c.logger == nil {
+		return
+	}
+
+	attrs := []any{"method", r.method, "path", r.path, "status", status, "duration", d, "nonce", nonce}
+	if id := CorrelationID(ctx); id != "" {
+		attrs = append(attrs, "correlation_id", id)
+	}
+	c.logger.DebugContext(ctx, "switchbot request", attrs...)
+}
+
+// unmarshal decodes a response body into a typed value, rejecting fields v
+// doesn't model when strict decoding is enabled.
+func (c *Client) unmarshal(data []byte, v any) error {
+	if !c.strict {
+		return json.Unmarshal(data, v)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+// withRequestTimeout applies the configured request timeout to ctx unless the
+// caller already set a deadline, which is always left as is.
+func (c *Client) withRequestTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.requestTimeout <= 0 {
+		return ctx, func() {}
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.requestTimeout)
+}
+
+// NextAvailable returns the earliest time a request would be sent without
+// waiting on the rate limiter, so schedulers can plan polling without
+// blocking. It doesn't consume any capacity. Without WithRateLimit it returns
+// the current time.
+func (c *Client) NextAvailable() time.Time {
+	now := time.Now()
+	if c.limiter == nil {
+		return now
+	}
+	return c.limiter.next(now)
+}
@@ -0,0 +1,152 @@
+package switchbot
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestWithTimeoutAppliesToDefaultClientOnly(t *testing.T) {
+	c, err := NewClient(testToken, testSecret, WithTimeout(3*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.httpClient.Timeout; got != 3*time.Second {
+		t.Errorf("default client timeout = %v, want 3s", got)
+	}
+
+	for _, order := range []string{"before", "after"} {
+		shared := &http.Client{Timeout: time.Minute}
+		opts := []Option{WithHTTPClient(shared), WithTimeout(time.Second)}
+		if order == "before" {
+			opts = []Option{WithTimeout(time.Second), WithHTTPClient(shared)}
+		}
+		c, err := NewClient(testToken, testSecret, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.httpClient != shared {
+			t.Errorf("WithTimeout %s WithHTTPClient: client replaced", order)
+		}
+		if shared.Timeout != time.Minute {
+			t.Errorf("WithTimeout %s WithHTTPClient: shared client timeout changed to %v", order, shared.Timeout)
+		}
+	}
+}
+
+func TestBuilderTimeout(t *testing.T) {
+	c, err := NewBuilder().Token(testToken).Secret(testSecret).Timeout(2 * time.Second).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.httpClient.Timeout; got != 2*time.Second {
+		t.Errorf("timeout = %v, want 2s", got)
+	}
+}
+
+func TestDefaultTimeout(t *testing.T) {
+	c, err := NewClient(testToken, testSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.httpClient.Timeout; got != DefaultTimeout {
+		t.Errorf("timeout = %v, want %v", got, DefaultTimeout)
+	}
+}
+
+// slowHandler answers successfully after delay, or gives up when the request
+// is cancelled.
+func slowHandler(delay time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(delay):
+			success(w, r)
+		case <-r.Context().Done():
+		}
+	}
+}
+
+func TestRequestTimeoutWithoutDeadline(t *testing.T) {
+	c := newTestClient(t, slowHandler(time.Second), WithRequestTimeout(50*time.Millisecond))
+
+	start := time.Now()
+	err := c.do(context.Background(), http.MethodGet, "/devices", nil, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("request took %v, want about 50ms", elapsed)
+	}
+}
+
+func TestRequestTimeoutKeepsCallerDeadline(t *testing.T) {
+	c := newTestClient(t, slowHandler(150*time.Millisecond), WithRequestTimeout(50*time.Millisecond))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := c.do(ctx, http.MethodGet, "/devices", nil, nil); err != nil {
+		t.Fatalf("caller deadline was shortened: %v", err)
+	}
+}
+
+func TestMaxResponseBytes(t *testing.T) {
+	devices := make([]Device, 50)
+	for i := range devices {
+		devices[i] = Device{DeviceID: "DEV000000001", DeviceName: "Living Room Bot", DeviceType: DeviceTypeBot}
+	}
+	h := func(w http.ResponseWriter, r *http.Request) {
+		writeEnvelope(w, statusSuccess, deviceList{DeviceList: devices})
+	}
+
+	small := newTestClient(t, h, WithMaxResponseBytes(1024))
+	if _, err := small.Devices(context.Background()); !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("err = %v, want ErrResponseTooLarge", err)
+	}
+
+	large := newTestClient(t, h, WithMaxResponseBytes(1<<20))
+	if got, err := large.Devices(context.Background()); err != nil || len(got) != len(devices) {
+		t.Errorf("Devices under the limit = %d devices, %v", len(got), err)
+	}
+}
+
+func TestCompression(t *testing.T) {
+	body := `{"statusCode":100,"message":"success","body":{"deviceList":[{"deviceId":"DEV000000001","deviceType":"Bot"}]}}`
+	var acceptEncoding []string
+	h := func(w http.ResponseWriter, r *http.Request) {
+		acceptEncoding = append(acceptEncoding, r.Header.Get("Accept-Encoding"))
+		if r.URL.Query().Get("plain") != "" {
+			io.WriteString(w, body)
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		io.WriteString(gz, body)
+		gz.Close()
+	}
+	// DisableCompression stops the transport from negotiating and
+	// decompressing gzip itself, as WithCompression is meant for
+	transport := &http.Transport{DisableCompression: true}
+	c := newTestClient(t, h, WithHTTPClient(&http.Client{Transport: transport}), WithCompression(true))
+	ctx := context.Background()
+
+	var list deviceList
+	if err := c.do(ctx, http.MethodGet, "/devices", nil, &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.DeviceList) != 1 || list.DeviceList[0].DeviceID != "DEV000000001" {
+		t.Errorf("gzipped body decoded as %+v", list)
+	}
+
+	// A server ignoring Accept-Encoding still works
+	if err := c.do(ctx, http.MethodGet, "/devices?plain=1", nil, &list); err != nil {
+		t.Errorf("plain body: %v", err)
+	}
+
+	if len(acceptEncoding) != 2 || acceptEncoding[0] != "gzip" {
+		t.Errorf("Accept-Encoding = %q, want gzip", acceptEncoding)
+	}
+}
@@ -0,0 +1,47 @@
+package switchbot
+
+import (
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// minClockSkew is the smallest correction applied from a Date header. Date
+// has one second resolution, so smaller differences are noise.
+const minClockSkew = 2 * time.Second
+
+// clock tracks the offset between the local clock and SwitchBot's, as
+// learned from the Date header of a 401 response.
+type clock struct {
+	offset atomic.Int64 // nanoseconds to add to the local time
+}
+
+// now returns the local time corrected by the learned offset.
+func (k *clock) now() time.Time {
+	return time.Now().Add(time.Duration(k.offset.Load()))
+}
+
+// correct updates the offset from the Date header in h. It reports whether
+// the offset changed by at least minClockSkew, i.e. whether re-signing with
+// the corrected time could make a difference.
+func (k *clock) correct(h http.Header) bool {
+	date, err := http.ParseTime(h.Get("Date"))
+	if err != nil {
+		return false
+	}
+
+	offset := date.Sub(time.Now())
+	diff := offset - time.Duration(k.offset.Load())
+	if diff > -minClockSkew && diff < minClockSkew {
+		return false
+	}
+	k.offset.Store(int64(offset))
+	return true
+}
+
+// isUnauthorized reports whether err is an HTTP 401.
+func isUnauthorized(err error) bool {
+	var httpErr *HTTPError
+	return errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusUnauthorized
+}
@@ -0,0 +1,72 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// skewedServer pretends its clock runs skew ahead, rejecting requests whose
+// signed timestamp t is more than 5 seconds off with a 401 carrying its Date.
+// With badCredentials every request is rejected.
+func skewedServer(skew time.Duration, badCredentials bool, attempts *int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		*attempts++
+		now := time.Now().Add(skew)
+		ms, _ := strconv.ParseInt(r.Header.Get("t"), 10, 64)
+		signed := time.UnixMilli(ms)
+		if badCredentials || signed.Before(now.Add(-5*time.Second)) || signed.After(now.Add(5*time.Second)) {
+			w.Header().Set("Date", now.UTC().Format(http.TimeFormat))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		success(w, r)
+	}
+}
+
+func TestClockSkewRetry(t *testing.T) {
+	var attempts int
+	c := newTestClient(t, skewedServer(time.Hour, false, &attempts), WithClockSkewRetry(true))
+	ctx := context.Background()
+
+	if err := c.do(ctx, http.MethodGet, "/devices", nil, nil); err != nil {
+		t.Fatalf("request after skew correction: %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("%d attempts, want the 401 and one re-signed retry", attempts)
+	}
+
+	// The learned offset is kept for later requests
+	attempts = 0
+	if err := c.do(ctx, http.MethodGet, "/devices", nil, nil); err != nil || attempts != 1 {
+		t.Errorf("next request: err %v after %d attempts, want success first time", err, attempts)
+	}
+}
+
+func TestClockSkewRetryOnlyOnce(t *testing.T) {
+	var attempts int
+	c := newTestClient(t, skewedServer(time.Hour, true, &attempts), WithClockSkewRetry(true), WithRetry(3, 0))
+
+	err := c.do(context.Background(), http.MethodGet, "/devices", nil, nil)
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("err = %v, want a 401", err)
+	}
+	if attempts != 2 {
+		t.Errorf("%d attempts for bad credentials, want 2", attempts)
+	}
+}
+
+func TestClockSkewRetryOff(t *testing.T) {
+	var attempts int
+	c := newTestClient(t, skewedServer(time.Hour, false, &attempts))
+	if err := c.do(context.Background(), http.MethodGet, "/devices", nil, nil); !isUnauthorized(err) {
+		t.Errorf("err = %v, want a 401 without WithClockSkewRetry", err)
+	}
+	if attempts != 1 {
+		t.Errorf("%d attempts, want 1", attempts)
+	}
+}
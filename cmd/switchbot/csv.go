@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"slices"
+	"strconv"
+
+	"switchbot"
+)
+
+// statusRow is a device with its typed status, as returned by DeviceStatus.
+type statusRow struct {
+	device switchbot.Device
+	status any
+}
+
+// annotationPrefix starts the names of annotation columns, e.g.
+// "annotation.room".
+const annotationPrefix = "annotation."
+
+// writeDevicesCSV writes the device list with a header row. Annotations get
+// a column per key used by any device.
+func writeDevicesCSV(w io.Writer, devices []switchbot.Device) error {
+	var keys []string
+	for _, d := range devices {
+		for key := range d.Annotations {
+			if !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	slices.Sort(keys)
+
+	cw := csv.NewWriter(w)
+	header := []string{"deviceId", "deviceName", "deviceType", "hubDeviceId", "enableCloudService"}
+	for _, key := range keys {
+		header = append(header, annotationPrefix+key)
+	}
+	cw.Write(header)
+	for _, d := range devices {
+		record := []string{d.DeviceID, d.DeviceName, string(d.DeviceType), d.HubDeviceID, strconv.FormatBool(d.EnableCloudService)}
+		for _, key := range keys {
+			record = append(record, d.Annotations[key])
+		}
+		cw.Write(record)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// leadingColumns come first in a status CSV; the other columns are the
+// union of the fields of every status, sorted.
+var leadingColumns = []string{"deviceId", "deviceName", "deviceType"}
+
+// writeStatusCSV writes one row per status. Statuses of different types
+// have different fields, so the columns are the union of every field
+// reported, left empty for devices that don't have them. Field names and
+// values are those of the statuses' JSON encoding; nested values are written
+// as JSON. Device annotations are added as columns too.
+func writeStatusCSV(w io.Writer, rows []statusRow) error {
+	fields := make([]map[string]string, len(rows))
+	var columns []string
+	for i, r := range rows {
+		flat, err := flatten(r.status)
+		if err != nil {
+			return err
+		}
+		flat["deviceId"] = r.device.DeviceID
+		flat["deviceName"] = r.device.DeviceName
+		if r.device.DeviceType != "" {
+			flat["deviceType"] = string(r.device.DeviceType)
+		}
+		for key, value := range r.device.Annotations {
+			flat[annotationPrefix+key] = value
+		}
+		for k := range flat {
+			if !slices.Contains(leadingColumns, k) && !slices.Contains(columns, k) {
+				columns = append(columns, k)
+			}
+		}
+		fields[i] = flat
+	}
+	slices.Sort(columns)
+	columns = append(slices.Clone(leadingColumns), columns...)
+
+	cw := csv.NewWriter(w)
+	cw.Write(columns)
+	for _, flat := range fields {
+		record := make([]string, len(columns))
+		for i, col := range columns {
+			record[i] = flat[col]
+		}
+		cw.Write(record)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// flatten turns a status into its top-level JSON fields, leaving out the
+// "type" marker added for UnmarshalStatus.
+func flatten(status any) (map[string]string, error) {
+	b, err := json.Marshal(reported(status))
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	delete(m, "type")
+
+	flat := make(map[string]string, len(m))
+	for k, v := range m {
+		var s string
+		if err := json.Unmarshal(v, &s); err == nil {
+			flat[k] = s
+			continue
+		}
+		if string(v) != "null" {
+			flat[k] = string(v)
+		}
+	}
+	return flat, nil
+}
+
+// reported returns what to print for a status: the body as SwitchBot sent it
+// for device types the library doesn't model, rather than the wrapped form
+// UnknownStatus marshals to, and the status itself otherwise.
+func reported(status any) any {
+	if u, ok := status.(*switchbot.UnknownStatus); ok && u.Raw != nil {
+		return u.Raw
+	}
+	return status
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"switchbot"
+)
+
+// readCSV parses out into a map per row keyed by column, failing the test if
+// the header isn't want.
+func readCSV(t *testing.T, out []byte, want []string) []map[string]string {
+	t.Helper()
+	records, err := csv.NewReader(bytes.NewReader(out)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) == 0 || !slices.Equal(records[0], want) {
+		t.Fatalf("header = %v, want %v", records, want)
+	}
+	var rows []map[string]string
+	for _, record := range records[1:] {
+		row := make(map[string]string, len(record))
+		for i, col := range records[0] {
+			row[col] = record[i]
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func TestWriteDevicesCSV(t *testing.T) {
+	devices := []switchbot.Device{
+		{DeviceID: "METER01", DeviceName: "Bedroom", DeviceType: switchbot.DeviceTypeMeter, HubDeviceID: "HUB01", EnableCloudService: true},
+		{DeviceID: "BOT01", DeviceName: "Kettle, kitchen", DeviceType: switchbot.DeviceTypeBot},
+	}
+	var buf bytes.Buffer
+	if err := writeDevicesCSV(&buf, devices); err != nil {
+		t.Fatal(err)
+	}
+
+	rows := readCSV(t, buf.Bytes(), []string{"deviceId", "deviceName", "deviceType", "hubDeviceId", "enableCloudService"})
+	if len(rows) != 2 {
+		t.Fatalf("%d rows, want 2", len(rows))
+	}
+	want := map[string]string{"deviceId": "METER01", "deviceName": "Bedroom", "deviceType": "Meter", "hubDeviceId": "HUB01", "enableCloudService": "true"}
+	for col, v := range want {
+		if rows[0][col] != v {
+			t.Errorf("row 1 %s = %q, want %q", col, rows[0][col], v)
+		}
+	}
+	if rows[1]["deviceName"] != "Kettle, kitchen" {
+		t.Errorf("name with a comma = %q", rows[1]["deviceName"])
+	}
+}
+
+func TestWriteStatusCSV(t *testing.T) {
+	meter := &switchbot.MeterStatus{
+		BaseStatus:  switchbot.BaseStatus{DeviceID: "METER01", DeviceType: switchbot.DeviceTypeMeter, HubDeviceID: "HUB01"},
+		Temperature: 21.5,
+		Humidity:    48,
+	}
+	bot := &switchbot.BotStatus{
+		BaseStatus: switchbot.BaseStatus{DeviceID: "BOT01", DeviceType: switchbot.DeviceTypeBot},
+		Power:      switchbot.PowerOn,
+		DeviceMode: switchbot.BotModeSwitch,
+	}
+	unknown := &switchbot.UnknownStatus{
+		BaseStatus: switchbot.BaseStatus{DeviceID: "NEW01", DeviceType: "Future Gadget"},
+		Raw:        json.RawMessage(`{"deviceId":"NEW01","deviceType":"Future Gadget","level":{"a":1}}`),
+	}
+	rows := []statusRow{
+		{device: switchbot.Device{DeviceID: "METER01", DeviceName: "Bedroom", DeviceType: switchbot.DeviceTypeMeter}, status: meter},
+		{device: switchbot.Device{DeviceID: "BOT01", DeviceName: "Kettle"}, status: bot},
+		{device: switchbot.Device{DeviceID: "NEW01", DeviceName: "Gadget"}, status: unknown},
+	}
+	var buf bytes.Buffer
+	if err := writeStatusCSV(&buf, rows); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(buf.Bytes())).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := records[0]
+	if !slices.Equal(header[:3], leadingColumns) {
+		t.Errorf("header starts %v, want %v", header[:3], leadingColumns)
+	}
+	if !slices.IsSorted(header[3:]) || slices.Contains(header, "type") {
+		t.Errorf("header = %v, want the other columns sorted and no type marker", header)
+	}
+	got := readCSV(t, buf.Bytes(), header)
+	checks := []struct {
+		row      int
+		col, val string
+	}{
+		{0, "deviceName", "Bedroom"},
+		{0, "temperature", "21.5"},
+		{0, "humidity", "48"},
+		{0, "hubDeviceId", "HUB01"},
+		{0, "power", ""},
+		{1, "deviceType", "Bot"},
+		{1, "power", "on"},
+		{1, "temperature", ""},
+		{2, "deviceType", "Future Gadget"},
+		{2, "level", `{"a":1}`},
+	}
+	for _, c := range checks {
+		if v := got[c.row][c.col]; v != c.val {
+			t.Errorf("row %d %s = %q, want %q", c.row+1, c.col, v, c.val)
+		}
+	}
+}
+
+func TestWriteDevicesCSVAnnotations(t *testing.T) {
+	devices := []switchbot.Device{
+		{DeviceID: "BOT01", DeviceType: switchbot.DeviceTypeBot, Annotations: map[string]string{"room": "kitchen", "tags": "morning"}},
+		{DeviceID: "METER01", DeviceType: switchbot.DeviceTypeMeter, Annotations: map[string]string{"room": "bedroom"}},
+		{DeviceID: "PLUG01", DeviceType: switchbot.DeviceTypePlug},
+	}
+	var buf bytes.Buffer
+	if err := writeDevicesCSV(&buf, devices); err != nil {
+		t.Fatal(err)
+	}
+
+	rows := readCSV(t, buf.Bytes(), []string{"deviceId", "deviceName", "deviceType", "hubDeviceId", "enableCloudService", "annotation.room", "annotation.tags"})
+	want := []map[string]string{
+		{"annotation.room": "kitchen", "annotation.tags": "morning"},
+		{"annotation.room": "bedroom", "annotation.tags": ""},
+		{"annotation.room": "", "annotation.tags": ""},
+	}
+	for i, w := range want {
+		for col, v := range w {
+			if rows[i][col] != v {
+				t.Errorf("row %d %s = %q, want %q", i+1, col, rows[i][col], v)
+			}
+		}
+	}
+}
+
+func TestWriteStatusCSVAnnotations(t *testing.T) {
+	rows := []statusRow{
+		{
+			device: switchbot.Device{DeviceID: "BOT01", Annotations: map[string]string{"room": "kitchen"}},
+			status: &switchbot.BotStatus{BaseStatus: switchbot.BaseStatus{DeviceID: "BOT01", DeviceType: switchbot.DeviceTypeBot}},
+		},
+		{
+			device: switchbot.Device{DeviceID: "TV01", Annotations: map[string]string{"tags": "lounge"}},
+			status: &switchbot.UnknownStatus{BaseStatus: switchbot.BaseStatus{DeviceID: "TV01"}},
+		},
+	}
+	var buf bytes.Buffer
+	if err := writeStatusCSV(&buf, rows); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(buf.Bytes())).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := readCSV(t, buf.Bytes(), records[0])
+	if got[0]["annotation.room"] != "kitchen" || got[0]["annotation.tags"] != "" {
+		t.Errorf("row 1 annotations = %q, %q, want kitchen and none", got[0]["annotation.room"], got[0]["annotation.tags"])
+	}
+	if got[1]["annotation.room"] != "" || got[1]["annotation.tags"] != "lounge" {
+		t.Errorf("row 2 annotations = %q, %q, want none and lounge", got[1]["annotation.room"], got[1]["annotation.tags"])
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"switchbot"
+)
+
+const usage = `usage: switchbot [-format text|json|csv] [-cache file] [devices | status [deviceId...] | annotate deviceId key [value]]
+
+With no command, lists the devices and prints the status of the first one.
+status without ids prints the status of every device. annotate sets a local
+annotation shown in listings, or removes it if no value is given; it needs
+-cache to be kept between runs.
+`
+
+// cacheTTL is how long a device list kept with -cache is used before it is
+// fetched again.
+const cacheTTL = time.Hour
+
+func main() {
+	format := flag.String("format", "text", "output format: text, json or csv")
+	cacheFile := flag.String("cache", "", "file to keep the device list and annotations in between runs")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *format != "text" && *format != "json" && *format != "csv" {
+		fmt.Printf("Error: unknown format %q\n", *format)
+		return
+	}
+
+	// Token and secret from environment variables
+	var opts []switchbot.Option
+	if *cacheFile != "" {
+		opts = append(opts, switchbot.WithDeviceCache(cacheTTL))
+	}
+	c, err := switchbot.NewClientFromEnv(opts...)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	if *cacheFile != "" {
+		if err := c.LoadCache(*cacheFile); err != nil {
+			fmt.Printf("Error loading cache: %v\n", err)
+			return
+		}
+		defer func() {
+			if err := c.SaveCache(*cacheFile); err != nil {
+				fmt.Printf("Error saving cache: %v\n", err)
+			}
+		}()
+	}
+
+	if flag.Arg(0) == "annotate" {
+		if flag.NArg() < 3 {
+			flag.Usage()
+			return
+		}
+		c.SetAnnotation(flag.Arg(1), flag.Arg(2), flag.Arg(3))
+		return
+	}
+
+	ctx := context.Background()
+
+	// List the devices on the account
+	devices, err := c.Devices(ctx)
+	if errors.Is(err, switchbot.ErrNoDevices) {
+		fmt.Println("No devices found.")
+		return
+	}
+	if err != nil {
+		fmt.Printf("Error calling /devices API: %v\n", err)
+		return
+	}
+
+	switch flag.Arg(0) {
+	case "":
+		printDevices(devices, "text")
+
+		// Use the first device in the response
+		deviceID := devices[0].DeviceID
+		fmt.Printf("Using deviceId: %s\n", deviceID)
+		printStatuses(ctx, c, devices[:1], "json")
+	case "devices":
+		printDevices(devices, *format)
+	case "status":
+		selected := devices
+		if ids := flag.Args()[1:]; len(ids) > 0 {
+			selected = nil
+			for _, id := range ids {
+				selected = append(selected, findDevice(c, devices, id))
+			}
+		}
+		printStatuses(ctx, c, selected, *format)
+	default:
+		flag.Usage()
+	}
+}
+
+// findDevice returns the device with the given id, or a Device carrying only
+// the id and its annotations if it isn't listed, e.g. an IR remote.
+func findDevice(c *switchbot.Client, devices []switchbot.Device, id string) switchbot.Device {
+	for _, d := range devices {
+		if d.DeviceID == id {
+			return d
+		}
+	}
+	return switchbot.Device{DeviceID: id, Annotations: c.Annotations(id)}
+}
+
+func printDevices(devices []switchbot.Device, format string) {
+	switch format {
+	case "csv":
+		if err := writeDevicesCSV(os.Stdout, devices); err != nil {
+			fmt.Printf("Error writing CSV: %v\n", err)
+		}
+	case "json":
+		printJSON(devices)
+	default:
+		for _, d := range devices {
+			fmt.Printf("%s\t%s\t%s", d.DeviceID, d.DeviceType, d.DeviceName)
+			keys := make([]string, 0, len(d.Annotations))
+			for key := range d.Annotations {
+				keys = append(keys, key)
+			}
+			slices.Sort(keys)
+			for _, key := range keys {
+				fmt.Printf("\t%s=%s", key, d.Annotations[key])
+			}
+			fmt.Println()
+		}
+	}
+}
+
+func printStatuses(ctx context.Context, c *switchbot.Client, devices []switchbot.Device, format string) {
+	rows := make([]statusRow, 0, len(devices))
+	for _, d := range devices {
+		status, err := c.DeviceStatus(ctx, d.DeviceID)
+		if err != nil {
+			fmt.Printf("Error calling /devices/{deviceId}/status API for %s: %v\n", d.DeviceID, err)
+			continue
+		}
+		rows = append(rows, statusRow{device: d, status: status})
+	}
+
+	if format == "csv" {
+		if err := writeStatusCSV(os.Stdout, rows); err != nil {
+			fmt.Printf("Error writing CSV: %v\n", err)
+		}
+		return
+	}
+	// Print the status with every field the API reported
+	for _, r := range rows {
+		printJSON(reported(r.status))
+	}
+}
+
+func printJSON(v any) {
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		fmt.Printf("Error encoding status: %v\n", err)
+		return
+	}
+	fmt.Println(string(out))
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"testing"
+
+	"switchbot"
+)
+
+func TestFindDevice(t *testing.T) {
+	c, err := switchbot.NewClient("test-token-0123456789abcdef", "test-secret-0123456789abcdef")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.SetAnnotation("TV01", "room", "lounge")
+	devices := []switchbot.Device{{DeviceID: "BOT01", DeviceName: "Kettle"}}
+
+	if d := findDevice(c, devices, "BOT01"); d.DeviceName != "Kettle" {
+		t.Errorf("listed device = %+v, want the Kettle", d)
+	}
+	d := findDevice(c, devices, "TV01")
+	if d.DeviceID != "TV01" || d.Annotations["room"] != "lounge" {
+		t.Errorf("unlisted device = %+v, want TV01 with its annotations", d)
+	}
+}
@@ -0,0 +1,37 @@
+package switchbot
+
+import (
+	"context"
+	"sync"
+)
+
+// commandLocks holds a lock per device that is kept while a command is sent,
+// so commands to one device go out one at a time and helpers that send
+// several, such as LightTurnOnAt, aren't interleaved with other commands to
+// the same device. The zero value is ready to use.
+type commandLocks struct {
+	mu    sync.Mutex
+	locks map[string]chan struct{}
+}
+
+// lock locks the device and returns the function that unlocks it, or ctx's
+// error if ctx is done first.
+func (l *commandLocks) lock(ctx context.Context, id string) (func(), error) {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = make(map[string]chan struct{})
+	}
+	ch, ok := l.locks[id]
+	if !ok {
+		ch = make(chan struct{}, 1)
+		l.locks[id] = ch
+	}
+	l.mu.Unlock()
+
+	select {
+	case ch <- struct{}{}:
+		return func() { <-ch }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
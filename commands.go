@@ -0,0 +1,110 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Command types accepted by the commands endpoint.
+const (
+	CommandTypeCommand   = "command"
+	CommandTypeCustomize = "customize"
+)
+
+// DefaultParameter is the parameter of commands that take none. SwitchBot
+// expects the literal string "default" rather than an absent field.
+const DefaultParameter = "default"
+
+// Command is the body of a POST to /devices/{deviceId}/commands.
+//
+// Parameter is sent as is: a string such as "0,ff,80" or a JSON object for
+// the commands that take one. A nil Parameter is sent as DefaultParameter,
+// and an empty CommandType as CommandTypeCommand.
+type Command struct {
+	Command     string `json:"command"`
+	Parameter   any    `json:"parameter"`
+	CommandType string `json:"commandType"`
+}
+
+// normalized fills in the defaults described on Command.
+func (cmd Command) normalized() Command {
+	if cmd.Parameter == nil {
+		cmd.Parameter = DefaultParameter
+	}
+	if cmd.CommandType == "" {
+		cmd.CommandType = CommandTypeCommand
+	}
+	return cmd
+}
+
+// SendCommand sends cmd to the device with the given id.
+//
+// Any status cached for the device by WithStatusETags is evicted once the
+// command has been sent, even if it failed, since the device may have acted
+// on it; the next status read fetches a fresh body. The device list cache
+// (WithDeviceCache) is not affected, as commands don't change it.
+//
+// With WithCommandTimeouts and no deadline on ctx, the command is bounded by
+// the timeout for the device's type. With WithHubCheck, commands to a
+// Bluetooth device without a hub fail with ErrNoHub before being sent.
+//
+// Commands to the same device are sent one at a time; commands to different
+// devices still run concurrently.
+//
+// opts override the client's timeout and retries for this command only; see
+// CallOption.
+func (c *Client) SendCommand(ctx context.Context, id string, cmd Command, opts ...CallOption) error {
+	ctx, cancelCall := withCallOptions(ctx, opts)
+	defer cancelCall()
+	unlock, err := c.commandLocks.lock(ctx, id)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return c.sendCommand(ctx, id, cmd)
+}
+
+// sendCommand is SendCommand for a caller holding the device's command lock.
+func (c *Client) sendCommand(ctx context.Context, id string, cmd Command) error {
+	cmd = cmd.normalized()
+	if err := c.checkHub(ctx, id); err != nil {
+		return err
+	}
+	if err := c.cooldown.wait(ctx, id); err != nil {
+		return err
+	}
+	ctx, cancel := c.commandContext(ctx, id)
+	defer cancel()
+	err := c.do(ctx, http.MethodPost, "/devices/"+url.PathEscape(id)+"/commands", cmd, nil)
+	c.etags.evict(id)
+	c.results.put(id, CommandRecord{Command: cmd, Err: err, At: time.Now()})
+	return err
+}
+
+// ErrNotVerified is returned by SendVerifiedCommand when the command was
+// accepted but the device didn't reach the expected state in time. It wraps
+// the ErrStateTimeout from polling.
+var ErrNotVerified = errors.New("command sent but not verified")
+
+// SendVerifiedCommand sends cmd, then polls the device's typed status with
+// WaitForState until verify reports true or timeout has passed. A nil verify
+// skips verification. Errors sending the command are returned as is; a
+// failed verification wraps ErrNotVerified. This catches commands SwitchBot
+// accepts but the device never acts on, which is common for devices behind a
+// hub out of Bluetooth range.
+func (c *Client) SendVerifiedCommand(ctx context.Context, id string, cmd Command, verify func(status any) bool, timeout time.Duration) error {
+	if err := c.SendCommand(ctx, id, cmd); err != nil {
+		return err
+	}
+	if verify == nil {
+		return nil
+	}
+	if _, err := c.WaitForState(ctx, id, verify, timeout); err != nil {
+		return fmt.Errorf("%w: %s to %s: %w", ErrNotVerified, cmd.Command, id, err)
+	}
+	return nil
+}
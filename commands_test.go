@@ -0,0 +1,191 @@
+package switchbot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCommandDefaultParameter(t *testing.T) {
+	var body string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		success(w, r)
+	})
+	ctx := context.Background()
+	want := `{"command":"turnOn","parameter":"default","commandType":"command"}`
+
+	if err := c.SendCommand(ctx, "DEV000000001", Command{Command: "turnOn"}); err != nil {
+		t.Fatal(err)
+	}
+	if body != want {
+		t.Errorf("defaults filled in: body = %s, want %s", body, want)
+	}
+
+	if err := c.SendCommand(ctx, "DEV000000001", Command{Command: "turnOn", Parameter: DefaultParameter, CommandType: CommandTypeCommand}); err != nil {
+		t.Fatal(err)
+	}
+	if body != want {
+		t.Errorf("explicit defaults: body = %s, want %s", body, want)
+	}
+}
+
+func TestCommandNormalizedKeepsValues(t *testing.T) {
+	cmd := Command{Command: "Power", Parameter: "0,ff,80", CommandType: CommandTypeCustomize}.normalized()
+	if cmd.Parameter != "0,ff,80" || cmd.CommandType != CommandTypeCustomize {
+		t.Errorf("normalized() = %+v, want the values kept", cmd)
+	}
+}
+
+func TestCommandEvictsCachedStatus(t *testing.T) {
+	for _, fail := range []bool{false, true} {
+		var listReads int
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case apiVersion + "/devices":
+				listReads++
+				writeEnvelope(w, statusSuccess, deviceList{DeviceList: []Device{{DeviceID: "DEV000000003", DeviceType: DeviceTypePlugMiniUS}}})
+			case apiVersion + "/devices/DEV000000003/status":
+				if r.Header.Get("If-None-Match") == `"v1"` {
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				w.Header().Set("ETag", `"v1"`)
+				writeEnvelope(w, statusSuccess, PlugStatus{BaseStatus: BaseStatus{DeviceID: "DEV000000003", DeviceType: DeviceTypePlugMiniUS}})
+			case apiVersion + "/devices/DEV000000003/commands":
+				if fail {
+					writeAPIError(w, 161, "device offline")
+					return
+				}
+				success(w, r)
+			}
+		}, WithStatusETags(), WithDeviceCache(time.Hour))
+		ctx := context.Background()
+
+		if _, err := c.Devices(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := c.DeviceStatus(ctx, "DEV000000003"); err != nil {
+			t.Fatal(err)
+		}
+		err := c.SendCommand(ctx, "DEV000000003", Command{Command: "turnOff"})
+		if fail != (err != nil) {
+			t.Fatalf("fail %v: SendCommand err = %v", fail, err)
+		}
+
+		status, err := c.DeviceStatusConditional(ctx, "DEV000000003")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status.NotModified {
+			t.Errorf("fail %v: status after a command served from the cache", fail)
+		}
+		if _, err := c.Devices(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if listReads != 1 {
+			t.Errorf("fail %v: device list fetched %d times, want the cached list kept across commands", fail, listReads)
+		}
+	}
+}
+
+// botServer fakes a Bot whose power follows turnOn and turnOff commands,
+// unless stuck is set.
+type botServer struct {
+	mu       sync.Mutex
+	power    PowerState
+	stuck    bool
+	commands int
+}
+
+func (s *botServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if strings.HasSuffix(r.URL.Path, "/commands") {
+		var cmd Command
+		json.NewDecoder(r.Body).Decode(&cmd)
+		s.commands++
+		if !s.stuck {
+			s.power = PowerState(strings.ToLower(strings.TrimPrefix(cmd.Command, "turn")))
+		}
+		success(w, r)
+		return
+	}
+	writeEnvelope(w, statusSuccess, map[string]any{"deviceId": "BOT01", "deviceType": "Bot", "power": s.power})
+}
+
+func poweredOn(status any) bool {
+	s, ok := status.(*BotStatus)
+	return ok && s.Power == PowerOn
+}
+
+func TestSendVerifiedCommand(t *testing.T) {
+	srv := &botServer{power: PowerOff}
+	c := newTestClient(t, srv.ServeHTTP, WithPollInterval(5*time.Millisecond))
+
+	if err := c.SendVerifiedCommand(context.Background(), "BOT01", Command{Command: "turnOn"}, poweredOn, time.Second); err != nil {
+		t.Fatalf("verified command: %v", err)
+	}
+	if err := c.SendVerifiedCommand(context.Background(), "BOT01", Command{Command: "turnOff"}, nil, time.Second); err != nil {
+		t.Fatalf("unverified command: %v", err)
+	}
+	if srv.commands != 2 {
+		t.Errorf("sent %d commands, want 2", srv.commands)
+	}
+}
+
+func TestSendVerifiedCommandTimeout(t *testing.T) {
+	srv := &botServer{power: PowerOff, stuck: true}
+	c := newTestClient(t, srv.ServeHTTP, WithPollInterval(5*time.Millisecond))
+
+	err := c.SendVerifiedCommand(context.Background(), "BOT01", Command{Command: "turnOn"}, poweredOn, 50*time.Millisecond)
+	if !errors.Is(err, ErrNotVerified) || !errors.Is(err, ErrStateTimeout) {
+		t.Errorf("err = %v, want ErrNotVerified wrapping ErrStateTimeout", err)
+	}
+	if srv.commands != 1 {
+		t.Errorf("sent %d commands, want 1", srv.commands)
+	}
+}
+
+func TestSendVerifiedCommandSendError(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeAPIError(w, statusDeviceNotFound, "device not found")
+	})
+	err := c.SendVerifiedCommand(context.Background(), "BOT01", Command{Command: "turnOn"}, poweredOn, time.Second)
+	if !errors.Is(err, ErrDeviceNotFound) || errors.Is(err, ErrNotVerified) {
+		t.Errorf("err = %v, want the send error unwrapped", err)
+	}
+}
+
+func TestSendCommandWaitsForDeviceLock(t *testing.T) {
+	c, srv := newCommandClient(t)
+	unlock, err := c.commandLocks.lock(context.Background(), "BOT01")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := c.BotPress(ctx, "BOT01"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want the context's deadline while the device is locked", err)
+	}
+	if err := c.BotPress(context.Background(), "BOT02"); err != nil {
+		t.Errorf("other device: %v", err)
+	}
+
+	unlock()
+	if err := c.BotPress(context.Background(), "BOT01"); err != nil {
+		t.Fatal(err)
+	}
+	wantCommands(t, srv.commands(),
+		sentCommand{"BOT02", Command{"press", DefaultParameter, CommandTypeCommand}},
+		sentCommand{"BOT01", Command{"press", DefaultParameter, CommandTypeCommand}},
+	)
+}
@@ -0,0 +1,64 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCooldown is returned by commands sent to a device too soon after the
+// previous one, when WithCommandCooldown is set to CooldownReject.
+var ErrCooldown = errors.New("command cooldown in effect")
+
+// CooldownPolicy decides what happens to a command sent within the cooldown.
+type CooldownPolicy int
+
+const (
+	// CooldownWait delays the command until the cooldown has passed.
+	CooldownWait CooldownPolicy = iota
+	// CooldownReject fails the command with ErrCooldown.
+	CooldownReject
+)
+
+// cooldown tracks when each device may next receive a command.
+type cooldown struct {
+	interval time.Duration
+	policy   CooldownPolicy
+
+	mu   sync.Mutex
+	next map[string]time.Time
+}
+
+func newCooldown(interval time.Duration, policy CooldownPolicy) *cooldown {
+	return &cooldown{
+		interval: interval,
+		policy:   policy,
+		next:     make(map[string]time.Time),
+	}
+}
+
+// wait claims the next command slot for id, blocking until it starts under
+// CooldownWait. A nil *cooldown never waits.
+func (cd *cooldown) wait(ctx context.Context, id string) error {
+	if cd == nil {
+		return nil
+	}
+
+	cd.mu.Lock()
+	now := time.Now()
+	at := cd.next[id]
+	if at.Before(now) {
+		at = now
+	}
+	if at.After(now) && cd.policy == CooldownReject {
+		cd.mu.Unlock()
+		return fmt.Errorf("%w: %s for another %s", ErrCooldown, id, at.Sub(now).Round(time.Millisecond))
+	}
+	// Claiming the slot up front queues concurrent waiters one interval apart
+	cd.next[id] = at.Add(cd.interval)
+	cd.mu.Unlock()
+
+	return sleep(ctx, at.Sub(now))
+}
@@ -0,0 +1,59 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCommandCooldownReject(t *testing.T) {
+	c, srv := newCommandClient(t, WithCommandCooldown(time.Minute, CooldownReject))
+	ctx := context.Background()
+
+	if err := c.BotPress(ctx, "DEV000000001"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.BotPress(ctx, "DEV000000001"); !errors.Is(err, ErrCooldown) {
+		t.Errorf("second press: err = %v, want ErrCooldown", err)
+	}
+	// Other devices have their own cooldown
+	if err := c.BotPress(ctx, "DEV000000007"); err != nil {
+		t.Errorf("other device: %v", err)
+	}
+	if n := len(srv.commands()); n != 2 {
+		t.Errorf("sent %d commands, want 2", n)
+	}
+}
+
+func TestCommandCooldownWait(t *testing.T) {
+	const interval = 100 * time.Millisecond
+	c, srv := newCommandClient(t, WithCommandCooldown(interval, CooldownWait))
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		if err := c.BotPress(ctx, "DEV000000001"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < interval {
+		t.Errorf("two commands took %v, want at least the %v cooldown", elapsed, interval)
+	}
+	if n := len(srv.commands()); n != 2 {
+		t.Errorf("sent %d commands, want 2", n)
+	}
+}
+
+func TestCommandCooldownWaitCancelled(t *testing.T) {
+	c, _ := newCommandClient(t, WithCommandCooldown(time.Minute, CooldownWait))
+	if err := c.BotPress(context.Background(), "DEV000000001"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := c.BotPress(ctx, "DEV000000001"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want the context's deadline", err)
+	}
+}
@@ -0,0 +1,61 @@
+package switchbot
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Environment variables read by NewClientFromEnv.
+const (
+	EnvToken  = "SWITCHBOT_TOKEN"
+	EnvSecret = "SWITCHBOT_API_KEY"
+)
+
+// ErrInvalidCredentials is returned by ValidateCredentials, and so by the
+// constructors, when the token or secret is clearly unusable.
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// Token and secret lengths as issued by the SwitchBot app (v1.1): the token is
+// a 96 character hex string and the secret a 32 character one. Validation
+// only uses them to spot a swapped pair, not to reject other lengths.
+const (
+	typicalTokenLen  = 96
+	typicalSecretLen = 32
+	minSecretLen     = 16
+)
+
+// ValidateCredentials is a cheap sanity check on a token and secret, run
+// before any request is signed. It rejects empty values, values containing
+// whitespace, secrets too short to be a real signing key, and pairs that look
+// swapped (a secret shaped like a token and vice versa). It cannot tell
+// whether the credentials are actually valid; only the API can.
+func ValidateCredentials(token, secret string) error {
+	switch {
+	case token == "":
+		return fmt.Errorf("%w: token is empty", ErrInvalidCredentials)
+	case secret == "":
+		return fmt.Errorf("%w: secret is empty", ErrInvalidCredentials)
+	case strings.ContainsAny(token, " \t\r\n"):
+		return fmt.Errorf("%w: token contains whitespace", ErrInvalidCredentials)
+	case strings.ContainsAny(secret, " \t\r\n"):
+		return fmt.Errorf("%w: secret contains whitespace", ErrInvalidCredentials)
+	case len(token) <= typicalSecretLen && len(secret) >= typicalTokenLen:
+		return fmt.Errorf("%w: token and secret appear to be swapped", ErrInvalidCredentials)
+	case len(secret) < minSecretLen:
+		return fmt.Errorf("%w: secret is too short to be a signing key", ErrInvalidCredentials)
+	}
+	return nil
+}
+
+// NewClientFromEnv returns a Client using the token in SWITCHBOT_TOKEN and
+// the secret in SWITCHBOT_API_KEY.
+func NewClientFromEnv(opts ...Option) (*Client, error) {
+	token := os.Getenv(EnvToken)
+	secret := os.Getenv(EnvSecret)
+	if token == "" || secret == "" {
+		return nil, fmt.Errorf("%w: %s or %s environment variable is not set", ErrInvalidCredentials, EnvToken, EnvSecret)
+	}
+	return NewClient(token, secret, opts...)
+}
@@ -0,0 +1,55 @@
+package switchbot
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateCredentials(t *testing.T) {
+	token := strings.Repeat("a1", typicalTokenLen/2)
+	secret := strings.Repeat("b2", typicalSecretLen/2)
+
+	tests := []struct {
+		name          string
+		token, secret string
+		wantErr       bool
+	}{
+		{"valid", token, secret, false},
+		{"empty token", "", secret, true},
+		{"empty secret", token, "", true},
+		{"both empty", "", "", true},
+		{"swapped", secret, token, true},
+		{"whitespace in token", token + "\n", secret, true},
+		{"whitespace in secret", token, " " + secret, true},
+		{"short secret", token, "abc", true},
+	}
+	for _, tt := range tests {
+		err := ValidateCredentials(tt.token, tt.secret)
+		if tt.wantErr != (err != nil) {
+			t.Errorf("%s: err = %v, want error %v", tt.name, err, tt.wantErr)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidCredentials) {
+			t.Errorf("%s: err = %v, want ErrInvalidCredentials", tt.name, err)
+		}
+	}
+}
+
+func TestNewClientValidatesCredentials(t *testing.T) {
+	if _, err := NewClient("", testSecret); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("NewClient with empty token: err = %v, want ErrInvalidCredentials", err)
+	}
+}
+
+func TestNewClientFromEnv(t *testing.T) {
+	t.Setenv(EnvToken, "")
+	t.Setenv(EnvSecret, testSecret)
+	if _, err := NewClientFromEnv(); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("unset token: err = %v, want ErrInvalidCredentials", err)
+	}
+
+	t.Setenv(EnvToken, testToken)
+	if _, err := NewClientFromEnv(); err != nil {
+		t.Errorf("NewClientFromEnv: %v", err)
+	}
+}
@@ -1,7 +1,7 @@
 #!/bin/bash
 
-GOOS=linux GOARCH=amd64 go build -o switchbot-linux-amd64
+GOOS=linux GOARCH=amd64 go build -o switchbot-linux-amd64 ./cmd/switchbot
 
-GOOS=windows GOARCH=amd64 go build -o switchbot-windows-amd64.exe
+GOOS=windows GOARCH=amd64 go build -o switchbot-windows-amd64.exe ./cmd/switchbot
 
-GOOS=darwin GOARCH=arm64 go build -o switchbot-darwin-arm64
+GOOS=darwin GOARCH=arm64 go build -o switchbot-darwin-arm64 ./cmd/switchbot
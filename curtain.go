@@ -0,0 +1,87 @@
+package switchbot
+
+import (
+	"context"
+	"fmt"
+)
+
+// CurtainMode is the moveMode of a Curtain setPosition command, trading speed
+// for noise.
+type CurtainMode string
+
+const (
+	CurtainModePerformance CurtainMode = "0"
+	CurtainModeSilent      CurtainMode = "1"
+	CurtainModeDefault     CurtainMode = "ff"
+)
+
+// CurtainStatus is the status of a Curtain or Curtain 3.
+//
+// SlidePosition runs from 0 (open) to 100 (closed).
+type CurtainStatus struct {
+	BaseStatus
+	Version       string  `json:"version"`
+	Calibrate     bool    `json:"calibrate"`
+	Group         bool    `json:"group"`
+	Moving        bool    `json:"moving"`
+	Battery       Battery `json:"battery"`
+	SlidePosition int     `json:"slidePosition"`
+
+	// LightLevel is "bright" or "dim", from the solar panel's light
+	// sensor. Only some firmware reports it.
+	LightLevel string `json:"lightLevel,omitempty"`
+}
+
+// NeedsCalibration reports whether the curtain hasn't been calibrated, in
+// which case positions are unreliable. The API can't start a calibration;
+// it has to be done from the SwitchBot app.
+func (s *CurtainStatus) NeedsCalibration() bool {
+	return !s.Calibrate
+}
+
+// MarshalJSON adds the "type" field UnmarshalStatus uses to restore the
+// status.
+func (s CurtainStatus) MarshalJSON() ([]byte, error) {
+	type plain CurtainStatus
+	return marshalStatus(statusKindCurtain, plain(s))
+}
+
+// CurtainStatus fetches the status of the Curtain or Curtain 3 with the given
+// id.
+func (c *Client) CurtainStatus(ctx context.Context, id string) (*CurtainStatus, error) {
+	return typedStatus[CurtainStatus](ctx, c, id)
+}
+
+// CurtainSetPosition moves the Curtain to position, from 0 (open) to 100
+// (closed), at the speed given by mode.
+//
+// The parameter is sent as "index,mode,position", e.g. "0,1,75" for a silent
+// move to 75%. The index is always 0. The Curtain 3 takes the same
+// parameter.
+func (c *Client) CurtainSetPosition(ctx context.Context, id string, position int, mode CurtainMode) error {
+	if position < 0 || position > 100 {
+		return fmt.Errorf("%w: curtain position must be between 0 and 100, got %d", ErrInvalidParameter, position)
+	}
+	switch mode {
+	case CurtainModePerformance, CurtainModeSilent, CurtainModeDefault:
+	case "":
+		mode = CurtainModeDefault
+	default:
+		return fmt.Errorf("%w: unknown curtain mode %q", ErrInvalidParameter, mode)
+	}
+
+	return c.SendCommand(ctx, id, Command{
+		Command:   "setPosition",
+		Parameter: fmt.Sprintf("0,%s,%d", mode, position),
+	})
+}
+
+// CurtainOpen fully opens the Curtain.
+func (c *Client) CurtainOpen(ctx context.Context, id string) error {
+	return c.SendCommand(ctx, id, Command{Command: "turnOn", Parameter: DefaultParameter})
+}
+
+// CurtainClose fully closes the Curtain.
+func (c *Client) CurtainClose(ctx context.Context, id string) error {
+	return c.SendCommand(ctx, id, Command{Command: "turnOff", Parameter: DefaultParameter})
+}
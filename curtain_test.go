@@ -0,0 +1,63 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCurtainSetPositionParameter(t *testing.T) {
+	tests := []struct {
+		mode CurtainMode
+		want string
+	}{
+		{CurtainModePerformance, "0,0,75"},
+		{CurtainModeSilent, "0,1,75"},
+		{CurtainModeDefault, "0,ff,75"},
+		{"", "0,ff,75"},
+	}
+	for _, tt := range tests {
+		c, srv := newCommandClient(t)
+		if err := c.CurtainSetPosition(context.Background(), "DEV000000002", 75, tt.mode); err != nil {
+			t.Fatalf("mode %q: %v", tt.mode, err)
+		}
+		wantCommands(t, srv.commands(), sentCommand{"DEV000000002", Command{
+			Command:     "setPosition",
+			Parameter:   tt.want,
+			CommandType: CommandTypeCommand,
+		}})
+	}
+}
+
+func TestCurtainSetPositionInvalid(t *testing.T) {
+	c, srv := newCommandClient(t)
+	ctx := context.Background()
+	if err := c.CurtainSetPosition(ctx, "DEV000000002", 101, CurtainModeSilent); !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("position 101: err = %v, want ErrInvalidParameter", err)
+	}
+	if err := c.CurtainSetPosition(ctx, "DEV000000002", 50, "2"); !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("mode 2: err = %v, want ErrInvalidParameter", err)
+	}
+	wantCommands(t, srv.commands())
+}
+
+func TestCurtainNeedsCalibration(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    bool
+	}{
+		{"status_curtain", false},
+		{"status_curtain3", false},
+		{"status_curtain_uncalibrated", true},
+	}
+	for _, tt := range tests {
+		c := newTestClient(t, serveFixture(t, tt.fixture))
+		s, err := c.CurtainStatus(context.Background(), "ANY")
+		if err != nil {
+			t.Fatalf("%s: %v", tt.fixture, err)
+		}
+		if got := s.NeedsCalibration(); got != tt.want {
+			t.Errorf("%s: NeedsCalibration = %v, want %v", tt.fixture, got, tt.want)
+		}
+	}
+}
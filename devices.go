@@ -0,0 +1,149 @@
+package switchbot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+)
+
+// ErrNoDevices is returned by Devices when the account has no physical
+// devices, so callers can tell an empty account from a failed fetch.
+var ErrNoDevices = errors.New("no devices found")
+
+// Device is a physical SwitchBot device from the device list.
+type Device struct {
+	DeviceID           string     `json:"deviceId"`
+	DeviceName         string     `json:"deviceName"`
+	DeviceType         DeviceType `json:"deviceType"`
+	EnableCloudService bool       `json:"enableCloudService"`
+	HubDeviceID        string     `json:"hubDeviceId"`
+
+	// LockDeviceID is the lock a Keypad is paired with.
+	LockDeviceID string `json:"lockDeviceId,omitempty"`
+
+	// Annotations are the local annotations set with SetAnnotation. They
+	// don't come from SwitchBot.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// InfraredRemote is a virtual IR remote learned by a hub.
+type InfraredRemote struct {
+	DeviceID   string `json:"deviceId"`
+	DeviceName string `json:"deviceName"`
+	// RemoteType is the remote's category, e.g. "TV" or "DIY TV"; see
+	// NormalizeRemoteType.
+	RemoteType  string `json:"remoteType"`
+	HubDeviceID string `json:"hubDeviceId"`
+
+	// Annotations are the local annotations set with SetAnnotation. They
+	// don't come from SwitchBot.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// deviceList is the body of GET /devices.
+type deviceList struct {
+	DeviceList         []Device         `json:"deviceList"`
+	InfraredRemoteList []InfraredRemote `json:"infraredRemoteList"`
+}
+
+// UnmarshalJSON decodes the device list leniently, whatever WithStrictDecoding
+// says: older accounts omit infraredRemoteList, send null lists and leave out
+// or add fields, none of which should fail a listing. Missing lists decode as
+// empty.
+func (l *deviceList) UnmarshalJSON(data []byte) error {
+	type plain deviceList
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	if p.DeviceList == nil {
+		p.DeviceList = []Device{}
+	}
+	if p.InfraredRemoteList == nil {
+		p.InfraredRemoteList = []InfraredRemote{}
+	}
+	*l = deviceList(p)
+	return nil
+}
+
+// Devices lists the physical devices on the account, with their
+// annotations. It returns ErrNoDevices, rather than an empty slice, if there
+// are none.
+func (c *Client) Devices(ctx context.Context) ([]Device, error) {
+	list, err := c.deviceList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(list.DeviceList) == 0 {
+		return nil, ErrNoDevices
+	}
+	devices := slices.Clone(list.DeviceList)
+	c.annotate(devices)
+	return devices, nil
+}
+
+// InfraredRemotes lists the IR remotes on the account, with their
+// annotations.
+func (c *Client) InfraredRemotes(ctx context.Context) ([]InfraredRemote, error) {
+	list, err := c.deviceList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	remotes := slices.Clone(list.InfraredRemoteList)
+	c.annotateRemotes(remotes)
+	return remotes, nil
+}
+
+// controllableTypes lists the physical device types that accept commands.
+var controllableTypes = map[DeviceType]bool{
+	DeviceTypeAirPurifierVOC:       true,
+	DeviceTypeAirPurifierTableVOC:  true,
+	DeviceTypeAirPurifierPM25:      true,
+	DeviceTypeAirPurifierTablePM25: true,
+	DeviceTypeBot:                  true,
+	DeviceTypeCurtain:              true,
+	DeviceTypeCurtain3:             true,
+	DeviceTypeBlindTilt:            true,
+	DeviceTypePlug:                 true,
+	DeviceTypePlugMiniUS:           true,
+	DeviceTypePlugMiniJP:           true,
+	DeviceTypeColorBulb:            true,
+	DeviceTypeStripLight:           true,
+	DeviceTypeStripLight3:          true,
+	DeviceTypeFloorLamp:            true,
+	DeviceTypeCeilingLight:         true,
+	DeviceTypeCeilingLightPro:      true,
+	DeviceTypeLock:                 true,
+	DeviceTypeLockPro:              true,
+	DeviceTypeHumidifier:           true,
+	DeviceTypeCirculatorFan:        true,
+}
+
+// ControllableDevices lists the devices that accept commands: bots, curtains,
+// blind tilts, plugs, lights, locks, humidifiers, air purifiers and fans,
+// followed by every IR remote with DeviceType set to DeviceTypeInfraredRemote,
+// with their annotations. Sensors, meters and hubs are left out.
+func (c *Client) ControllableDevices(ctx context.Context) ([]Device, error) {
+	list, err := c.deviceList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var devices []Device
+	for _, d := range list.DeviceList {
+		if controllableTypes[d.DeviceType] {
+			devices = append(devices, d)
+		}
+	}
+	for _, r := range list.InfraredRemoteList {
+		devices = append(devices, Device{
+			DeviceID:    r.DeviceID,
+			DeviceName:  r.DeviceName,
+			DeviceType:  DeviceTypeInfraredRemote,
+			HubDeviceID: r.HubDeviceID,
+		})
+	}
+	c.annotate(devices)
+	return devices, nil
+}
@@ -0,0 +1,39 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestDevicesEmptyList(t *testing.T) {
+	bodies := map[string]any{
+		"empty lists":   map[string]any{"deviceList": []any{}, "infraredRemoteList": []any{}},
+		"null lists":    map[string]any{"deviceList": nil, "infraredRemoteList": nil},
+		"missing lists": map[string]any{},
+	}
+	for name, body := range bodies {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			writeEnvelope(w, statusSuccess, body)
+		})
+		devices, err := c.Devices(context.Background())
+		if !errors.Is(err, ErrNoDevices) {
+			t.Errorf("%s: Devices = %v, %v, want ErrNoDevices", name, devices, err)
+		}
+		remotes, err := c.InfraredRemotes(context.Background())
+		if err != nil || len(remotes) != 0 {
+			t.Errorf("%s: InfraredRemotes = %v, %v, want none", name, remotes, err)
+		}
+	}
+}
+
+func TestDevicesFetchFailure(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	_, err := c.Devices(context.Background())
+	if err == nil || errors.Is(err, ErrNoDevices) {
+		t.Errorf("err = %v, want a fetch error distinct from ErrNoDevices", err)
+	}
+}
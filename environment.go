@@ -0,0 +1,97 @@
+package switchbot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// environmentTypes are the device types that report temperature and
+// humidity.
+var environmentTypes = map[DeviceType]bool{
+	DeviceTypeMeter:        true,
+	DeviceTypeMeterPlus:    true,
+	DeviceTypeMeterPro:     true,
+	DeviceTypeMeterProCO2:  true,
+	DeviceTypeOutdoorMeter: true,
+	DeviceTypeHub2:         true,
+}
+
+// MeterReading is one device's entry in an EnvironmentReport. CO2 and
+// LightLevel are nil when the device doesn't measure them. If the device
+// couldn't be read, Err is set and the readings are zero.
+type MeterReading struct {
+	DeviceID   string
+	DeviceName string
+	DeviceType DeviceType
+
+	// Temperature is in degrees Celsius and Humidity in percent.
+	Temperature float64
+	Humidity    int
+	// CO2 is in ppm.
+	CO2 *int
+	// LightLevel is the Hub 2's 1–20 ambient light scale.
+	LightLevel *int
+
+	Err error
+}
+
+// TemperatureC returns Temperature in degrees Celsius, as reported.
+func (r MeterReading) TemperatureC() float64 {
+	return r.Temperature
+}
+
+// TemperatureF returns Temperature converted to degrees Fahrenheit, whatever
+// WithTemperatureUnit is set to.
+func (r MeterReading) TemperatureF() float64 {
+	return CelsiusToFahrenheit(r.Temperature)
+}
+
+// environmentFields are the status fields an EnvironmentReport reads,
+// whichever device type reports them.
+type environmentFields struct {
+	Temperature FlexFloat `json:"temperature"`
+	Humidity    FlexInt   `json:"humidity"`
+	CO2         *int      `json:"CO2"`
+	LightLevel  *int      `json:"lightLevel"`
+}
+
+// EnvironmentReport reads every meter and Hub 2 on the account and returns
+// their temperature and humidity, plus CO2 and light level where measured.
+// Other devices are skipped. Reads run concurrently within the client's
+// concurrency and rate limit; a device that can't be read gets an entry with
+// Err set rather than failing the report.
+func (c *Client) EnvironmentReport(ctx context.Context) ([]MeterReading, error) {
+	list, err := c.deviceList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var readings []MeterReading
+	for _, d := range list.DeviceList {
+		if environmentTypes[d.DeviceType] {
+			readings = append(readings, MeterReading{DeviceID: d.DeviceID, DeviceName: d.DeviceName, DeviceType: d.DeviceType})
+		}
+	}
+
+	c.forEach(len(readings), func(i int) {
+		r := &readings[i]
+		raw, err := c.DeviceStatusRaw(ctx, r.DeviceID)
+		if err != nil {
+			r.Err = err
+			return
+		}
+
+		var f environmentFields
+		if err := json.Unmarshal(raw, &f); err != nil {
+			r.Err = fmt.Errorf("%w: %s: %w", ErrDecode, r.DeviceType, err)
+			return
+		}
+		r.Temperature = float64(f.Temperature)
+		r.Humidity = int(f.Humidity)
+		r.CO2 = f.CO2
+		r.LightLevel = f.LightLevel
+	})
+
+	return readings, nil
+}
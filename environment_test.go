@@ -0,0 +1,59 @@
+package switchbot
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"testing"
+)
+
+func TestTemperatureCAndF(t *testing.T) {
+	tests := []struct{ c, f float64 }{
+		{0, 32},
+		{-40, -40},
+		{21.5, 70.7},
+		{37, 98.6},
+		{-12.3, 9.86},
+	}
+	for _, tt := range tests {
+		r := MeterReading{Temperature: tt.c}
+		s := &MeterStatus{Temperature: FlexFloat(tt.c)}
+		if r.TemperatureC() != tt.c || s.TemperatureC() != tt.c {
+			t.Errorf("%v°C: TemperatureC = %v, %v, want the reported value", tt.c, r.TemperatureC(), s.TemperatureC())
+		}
+		if got := r.TemperatureF(); math.Abs(got-tt.f) > 1e-9 {
+			t.Errorf("MeterReading %v°C: TemperatureF = %v, want %v", tt.c, got, tt.f)
+		}
+		if got := s.TemperatureF(); math.Abs(got-tt.f) > 1e-9 {
+			t.Errorf("MeterStatus %v°C: TemperatureF = %v, want %v", tt.c, got, tt.f)
+		}
+	}
+}
+
+func TestEnvironmentReportUnits(t *testing.T) {
+	devices := []Device{
+		{DeviceID: "METER01", DeviceName: "Bedroom", DeviceType: DeviceTypeMeterPlus},
+		{DeviceID: "PLUG01", DeviceName: "Lamp", DeviceType: DeviceTypePlug},
+	}
+	h := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == apiVersion+"/devices" {
+			writeEnvelope(w, statusSuccess, deviceList{DeviceList: devices})
+			return
+		}
+		writeEnvelope(w, statusSuccess, map[string]any{"deviceId": "METER01", "deviceType": "MeterPlus", "temperature": "25", "humidity": 40})
+	}
+	// The configured unit doesn't change what is reported
+	c := newTestClient(t, h, WithTemperatureUnit(Fahrenheit))
+
+	readings, err := c.EnvironmentReport(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(readings) != 1 {
+		t.Fatalf("readings = %+v, want only the meter", readings)
+	}
+	r := readings[0]
+	if r.Err != nil || r.Temperature != 25 || r.TemperatureC() != 25 || r.TemperatureF() != 77 || r.Humidity != 40 {
+		t.Errorf("reading = %+v, C %v, F %v, want 25°C and 77°F", r, r.TemperatureC(), r.TemperatureF())
+	}
+}
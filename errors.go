@@ -0,0 +1,93 @@
+package switchbot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+)
+
+// ErrInvalidParameter is returned when a command argument is rejected before
+// it is sent to the API.
+var ErrInvalidParameter = errors.New("invalid parameter")
+
+// ErrUnexpectedDeviceType is returned when a device-specific helper is used on
+// a device of another type.
+var ErrUnexpectedDeviceType = errors.New("unexpected device type")
+
+// ErrNotJSON is returned when a response isn't JSON, typically an HTML
+// error page from a gateway in front of the API. The error includes the
+// start of the body with credentials redacted.
+var ErrNotJSON = errors.New("response is not JSON")
+
+// ErrDecode is returned when a status body can't be decoded into the typed
+// status or the caller's struct.
+var ErrDecode = errors.New("error decoding status")
+
+// ErrResponseTooLarge is returned when a response body exceeds the limit set
+// by WithMaxResponseBytes.
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// HTTPError is returned when the API answers with a non-200 HTTP status.
+type HTTPError struct {
+	StatusCode int
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("API request failed with status code %d", e.StatusCode)
+}
+
+// APIError is returned when the response envelope carries a statusCode other
+// than 100 (success).
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Message)
+}
+
+// statusDeviceNotFound is the envelope statusCode for an unknown device id.
+const statusDeviceNotFound = 152
+
+// Is makes errors.Is(err, ErrDeviceNotFound) true for statusCode 152 and
+// errors.Is(err, ErrNoHub) true for statusCode 171, so these are reported
+// the same way whether they were caught by the client or by the API.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrDeviceNotFound:
+		return e.StatusCode == statusDeviceNotFound
+	case ErrNoHub:
+		return e.StatusCode == statusNoHub
+	}
+	return false
+}
+
+// maxSnippet bounds how much of an unexpected body is quoted in an error.
+const maxSnippet = 200
+
+// looksLikeJSON reports whether a response body should be decoded as JSON.
+// Only markup is rejected, by Content-Type or a leading '<', since a JSON
+// body with a wrong Content-Type still decodes fine.
+func looksLikeJSON(contentType string, body []byte) bool {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		switch mediaType {
+		case "text/html", "application/xhtml+xml", "text/xml", "application/xml":
+			return false
+		}
+	}
+	trimmed := bytes.TrimSpace(body)
+	return len(trimmed) == 0 || trimmed[0] != '<'
+}
+
+// snippet returns the start of body for an error message, on one line, with
+// the client's token and secret redacted.
+func (c *Client) snippet(body []byte) string {
+	s := c.scrub(strings.Join(strings.Fields(string(body)), " "))
+	if len(s) > maxSnippet {
+		s = s[:maxSnippet] + "..."
+	}
+	return s
+}
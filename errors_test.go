@@ -0,0 +1,67 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestHTMLErrorPage(t *testing.T) {
+	page := "<html>\n<head><title>502 Bad Gateway</title></head>\n<body>upstream for " + testToken + " failed</body>\n</html>"
+	for _, contentType := range []string{"text/html; charset=utf-8", "application/json"} {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", contentType)
+			io.WriteString(w, page)
+		})
+
+		err := c.do(context.Background(), http.MethodGet, "/devices", nil, nil)
+		if !errors.Is(err, ErrNotJSON) {
+			t.Fatalf("%s: err = %v, want ErrNotJSON", contentType, err)
+		}
+		msg := err.Error()
+		if !strings.Contains(msg, "502 Bad Gateway") {
+			t.Errorf("%s: error %q doesn't quote the body", contentType, msg)
+		}
+		if strings.Contains(msg, testToken) || strings.Contains(msg, "\n") {
+			t.Errorf("%s: snippet not redacted to one line: %q", contentType, msg)
+		}
+	}
+}
+
+func TestSnippetTruncates(t *testing.T) {
+	c := &Client{token: testToken, secret: testSecret}
+	s := c.snippet([]byte("<p>" + strings.Repeat("x", 2*maxSnippet) + "</p>"))
+	if len(s) != maxSnippet+len("...") || !strings.HasSuffix(s, "...") {
+		t.Errorf("snippet of %d bytes, want %d ending in ...", len(s), maxSnippet+3)
+	}
+}
+
+func TestDeviceNotFound(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeAPIError(w, statusDeviceNotFound, "device not found")
+	})
+	ctx := context.Background()
+
+	_, err := c.DeviceStatus(ctx, "DEV999999999")
+	if !errors.Is(err, ErrDeviceNotFound) {
+		t.Errorf("status: err = %v, want ErrDeviceNotFound", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != statusDeviceNotFound {
+		t.Errorf("status: err = %v, want the APIError kept", err)
+	}
+
+	// Wrapped further by a caller
+	err = fmt.Errorf("turning off: %w", c.SendCommand(ctx, "DEV999999999", Command{Command: "turnOff"}))
+	if !errors.Is(err, ErrDeviceNotFound) {
+		t.Errorf("command: err = %v, want ErrDeviceNotFound", err)
+	}
+
+	if errors.Is(&APIError{StatusCode: 161}, ErrDeviceNotFound) {
+		t.Error("statusCode 161 matches ErrDeviceNotFound")
+	}
+}
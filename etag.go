@@ -0,0 +1,56 @@
+package switchbot
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+type etagEntry struct {
+	etag string
+	raw  json.RawMessage
+}
+
+// etagCache remembers the last ETag and status body per deviceId. A nil
+// *etagCache is valid and never holds anything.
+type etagCache struct {
+	mu      sync.Mutex
+	entries map[string]etagEntry
+}
+
+func newETagCache() *etagCache {
+	return &etagCache{entries: make(map[string]etagEntry)}
+}
+
+func (e *etagCache) get(id string) (etagEntry, bool) {
+	if e == nil {
+		return etagEntry{}, false
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	entry, ok := e.entries[id]
+	return entry, ok
+}
+
+// put stores the status for id, or forgets it if the response had no ETag.
+func (e *etagCache) put(id, etag string, raw json.RawMessage) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if etag == "" {
+		delete(e.entries, id)
+		return
+	}
+	e.entries[id] = etagEntry{etag: etag, raw: raw}
+}
+
+// evict forgets the status for id.
+func (e *etagCache) evict(id string) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.entries, id)
+}
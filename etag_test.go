@@ -0,0 +1,97 @@
+package switchbot
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// etagServer serves a plug status with ETag "v1", answering 304 when the
+// request carries it, and accepts commands.
+type etagServer struct {
+	mu          sync.Mutex
+	ifNoneMatch []string
+}
+
+func (s *etagServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasSuffix(r.URL.Path, "/commands") {
+		success(w, r)
+		return
+	}
+	s.mu.Lock()
+	s.ifNoneMatch = append(s.ifNoneMatch, r.Header.Get("If-None-Match"))
+	s.mu.Unlock()
+	if r.Header.Get("If-None-Match") == `"v1"` {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("ETag", `"v1"`)
+	writeEnvelope(w, statusSuccess, PlugStatus{
+		BaseStatus: BaseStatus{DeviceID: "DEV000000003", DeviceType: DeviceTypePlugMiniUS},
+		Power:      PowerOn,
+	})
+}
+
+func TestStatusETagNotModified(t *testing.T) {
+	srv := &etagServer{}
+	c := newTestClient(t, srv.ServeHTTP, WithStatusETags())
+	ctx := context.Background()
+
+	first, err := c.DeviceStatusConditional(ctx, "DEV000000003")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.NotModified {
+		t.Error("first read reported NotModified")
+	}
+
+	second, err := c.DeviceStatusConditional(ctx, "DEV000000003")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !second.NotModified {
+		t.Error("304 not reported as NotModified")
+	}
+	plug, ok := second.Status.(*PlugStatus)
+	if !ok || plug.Power != PowerOn {
+		t.Errorf("status after 304 = %+v, want the remembered plug status", second.Status)
+	}
+
+	// A command evicts the remembered status
+	if err := c.SendCommand(ctx, "DEV000000003", Command{Command: "turnOff"}); err != nil {
+		t.Fatal(err)
+	}
+	third, err := c.DeviceStatusConditional(ctx, "DEV000000003")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if third.NotModified {
+		t.Error("status after a command served from the ETag cache")
+	}
+
+	want := []string{"", `"v1"`, ""}
+	if strings.Join(srv.ifNoneMatch, "|") != strings.Join(want, "|") {
+		t.Errorf("If-None-Match headers = %q, want %q", srv.ifNoneMatch, want)
+	}
+}
+
+func TestStatusETagsOff(t *testing.T) {
+	srv := &etagServer{}
+	c := newTestClient(t, srv.ServeHTTP)
+	for i := 0; i < 2; i++ {
+		status, err := c.DeviceStatusConditional(context.Background(), "DEV000000003")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status.NotModified {
+			t.Error("NotModified without WithStatusETags")
+		}
+	}
+	for _, h := range srv.ifNoneMatch {
+		if h != "" {
+			t.Errorf("If-None-Match %q sent without WithStatusETags", h)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package switchbot
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Webhook device types. Webhook events name devices differently from the
+// device list, e.g. a "Meter" reports as "WoMeter".
+const (
+	WebhookDeviceMeter        = "WoMeter"
+	WebhookDeviceMeterPlus    = "WoMeterPlus"
+	WebhookDeviceOutdoorMeter = "WoIOSensor"
+	WebhookDevicePlugUS       = "WoPlugUS"
+	WebhookDevicePlugJP       = "WoPlugJP"
+	WebhookDeviceContact      = "WoContact"
+	WebhookDeviceMotion       = "WoPresence"
+	WebhookDeviceLock         = "WoLock"
+	WebhookDeviceLockPro      = "WoLockPro"
+	WebhookDeviceKeypad       = "WoKeypad"
+	WebhookDeviceKeypadTouch  = "WoKeypadTouch"
+)
+
+// EventBase holds the context fields every webhook event carries.
+type EventBase struct {
+	DeviceType string `json:"deviceType"`
+	// DeviceMac is the device's MAC address, which is also its deviceId.
+	DeviceMac    string     `json:"deviceMac"`
+	TimeOfSample UnixMillis `json:"timeOfSample"`
+}
+
+// MeterEvent is a changeReport from a meter. Scale is "CELSIUS" or
+// "FAHRENHEIT" and applies to Temperature.
+type MeterEvent struct {
+	EventBase
+	Temperature FlexFloat `json:"temperature"`
+	Humidity    FlexInt   `json:"humidity"`
+	Scale       string    `json:"scale"`
+	Battery     Battery   `json:"battery,omitempty"`
+}
+
+// PlugEvent is a changeReport from a Plug Mini. PowerState is "ON" or "OFF".
+type PlugEvent struct {
+	EventBase
+	PowerState string `json:"powerState"`
+}
+
+// On reports whether the plug was switched on.
+func (e *PlugEvent) On() bool {
+	return e.PowerState == "ON"
+}
+
+// ContactEvent is a changeReport from a Contact Sensor.
+type ContactEvent struct {
+	EventBase
+	// DetectionState is "DETECTED" or "NOT_DETECTED" motion.
+	DetectionState string `json:"detectionState"`
+	// OpenState is "open", "close" or "timeOutNotClose".
+	OpenState string `json:"openState"`
+	// DoorMode is "IN_DOOR" or "OUT_DOOR", the direction last passed.
+	DoorMode   string `json:"doorMode,omitempty"`
+	Brightness string `json:"brightness,omitempty"`
+}
+
+// MotionEvent is a changeReport from a Motion Sensor.
+type MotionEvent struct {
+	EventBase
+	// DetectionState is "DETECTED" or "NOT_DETECTED".
+	DetectionState string `json:"detectionState"`
+}
+
+// Detected reports whether motion was detected.
+func (e *MotionEvent) Detected() bool {
+	return e.DetectionState == "DETECTED"
+}
+
+// LockEvent is a changeReport from a Smart Lock or Lock Pro. LockState is
+// upper case, e.g. "LOCKED", "UNLOCKED" or "JAMMED".
+type LockEvent struct {
+	EventBase
+	LockState string `json:"lockState"`
+}
+
+// KeypadEvent reports the outcome of a passcode change sent to a Keypad.
+// Unlocks made from the keypad are reported by the paired lock as a
+// LockEvent.
+type KeypadEvent struct {
+	EventBase
+	// EventName is e.g. "createKey" or "deleteKey".
+	EventName string `json:"eventName"`
+	// CommandID matches the command that requested the change.
+	CommandID string `json:"commandId,omitempty"`
+	// Result is "success", "failed" or "timeout".
+	Result string `json:"result"`
+}
+
+// Succeeded reports whether the change was applied.
+func (e *KeypadEvent) Succeeded() bool {
+	return e.Result == "success"
+}
+
+// UnknownEvent is returned by Decode for device types without a typed event.
+// Raw holds the undecoded context.
+type UnknownEvent struct {
+	EventBase
+	Raw json.RawMessage
+}
+
+// eventTypes maps a webhook device type to a constructor for its typed event.
+var eventTypes = map[string]func() any{
+	WebhookDeviceMeter:        func() any { return new(MeterEvent) },
+	WebhookDeviceMeterPlus:    func() any { return new(MeterEvent) },
+	WebhookDeviceOutdoorMeter: func() any { return new(MeterEvent) },
+	WebhookDevicePlugUS:       func() any { return new(PlugEvent) },
+	WebhookDevicePlugJP:       func() any { return new(PlugEvent) },
+	WebhookDeviceContact:      func() any { return new(ContactEvent) },
+	WebhookDeviceMotion:       func() any { return new(MotionEvent) },
+	WebhookDeviceLock:         func() any { return new(LockEvent) },
+	WebhookDeviceLockPro:      func() any { return new(LockEvent) },
+	WebhookDeviceKeypad:       func() any { return new(KeypadEvent) },
+	WebhookDeviceKeypadTouch:  func() any { return new(KeypadEvent) },
+}
+
+// Decode decodes the event context into the typed event for its device
+// type, e.g. *MeterEvent, the way DeviceStatus does for statuses. Device
+// types without a typed event are returned as *UnknownEvent.
+func (e WebhookEvent) Decode() (any, error) {
+	newEvent, ok := eventTypes[e.Context.DeviceType]
+	if !ok {
+		return &UnknownEvent{EventBase: e.Context.EventBase, Raw: e.Context.Raw}, nil
+	}
+
+	event := newEvent()
+	if err := json.Unmarshal(e.Context.Raw, event); err != nil {
+		return nil, fmt.Errorf("error decoding %s event: %w", e.Context.DeviceType, err)
+	}
+	return event, nil
+}
@@ -0,0 +1,84 @@
+package switchbot
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeEventFixtures(t *testing.T) {
+	tests := map[string]any{
+		"event_meter": &MeterEvent{
+			EventBase:   EventBase{WebhookDeviceMeter, "DEV000000004", 1700000000123},
+			Temperature: 22.5, Humidity: 31, Scale: "CELSIUS", Battery: 90,
+		},
+		"event_plug": &PlugEvent{
+			EventBase:  EventBase{WebhookDevicePlugUS, "DEV000000003", 1700000000456},
+			PowerState: "ON",
+		},
+		"event_contact": &ContactEvent{
+			EventBase:      EventBase{WebhookDeviceContact, "DEV000000028", 1700000000789},
+			DetectionState: "NOT_DETECTED", OpenState: "open", DoorMode: "OUT_DOOR", Brightness: "dim",
+		},
+		"event_motion": &MotionEvent{
+			EventBase:      EventBase{WebhookDeviceMotion, "DEV000000027", 1700000001000},
+			DetectionState: "DETECTED",
+		},
+	}
+	for name, want := range tests {
+		var event WebhookEvent
+		if err := json.Unmarshal(loadFixture(t, name), &event); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		got, err := event.Decode()
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: decoded %+v, want %+v", name, got, want)
+		}
+	}
+}
+
+func TestDecodeEventHelpers(t *testing.T) {
+	if !(&PlugEvent{PowerState: "ON"}).On() || (&PlugEvent{PowerState: "OFF"}).On() {
+		t.Error("PlugEvent.On doesn't follow PowerState")
+	}
+	if !(&MotionEvent{DetectionState: "DETECTED"}).Detected() || (&MotionEvent{DetectionState: "NOT_DETECTED"}).Detected() {
+		t.Error("MotionEvent.Detected doesn't follow DetectionState")
+	}
+}
+
+func TestDecodeUnknownEvent(t *testing.T) {
+	var event WebhookEvent
+	if err := json.Unmarshal(loadFixture(t, "event_unknown"), &event); err != nil {
+		t.Fatal(err)
+	}
+	got, err := event.Decode()
+	if err != nil {
+		t.Fatalf("Decode: %v, want an UnknownEvent rather than an error", err)
+	}
+	unknown, ok := got.(*UnknownEvent)
+	if !ok {
+		t.Fatalf("decoded %T, want *UnknownEvent", got)
+	}
+	if unknown.DeviceType != "WoHub2" || unknown.DeviceMac != "HUB000000001" || unknown.TimeOfSample != 1700000002000 {
+		t.Errorf("EventBase = %+v", unknown.EventBase)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(unknown.Raw, &fields); err != nil || fields["lightLevel"] != 10.0 {
+		t.Errorf("Raw = %s, %v, want the whole context", unknown.Raw, err)
+	}
+}
+
+func TestDecodeEventError(t *testing.T) {
+	var event WebhookEvent
+	body := `{"eventType":"changeReport","context":{"deviceType":"WoMeter","deviceMac":"DEV000000004","temperature":"warm"}}`
+	if err := json.Unmarshal([]byte(body), &event); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := event.Decode(); err == nil {
+		t.Errorf("decoded %+v, want an error for a non-numeric temperature", got)
+	}
+}
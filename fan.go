@@ -0,0 +1,196 @@
+package switchbot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// FanMode is the wind mode of a Circulator Fan.
+type FanMode string
+
+const (
+	FanModeDirect  FanMode = "direct"
+	FanModeNatural FanMode = "natural"
+	FanModeSleep   FanMode = "sleep"
+	FanModeBaby    FanMode = "baby"
+)
+
+// FanNightLight is the night light setting of a Circulator Fan.
+type FanNightLight string
+
+const (
+	FanNightLightOff FanNightLight = "off"
+	FanNightLight1   FanNightLight = "1"
+	FanNightLight2   FanNightLight = "2"
+)
+
+// Speed range accepted by FanSetWindSpeed.
+const (
+	FanMinSpeed = 1
+	FanMaxSpeed = 100
+)
+
+// FanStatus is the status of a Circulator Fan.
+type FanStatus struct {
+	BaseStatus
+	Version     string     `json:"version,omitempty"`
+	Battery     Battery    `json:"battery,omitempty"`
+	Power       PowerState `json:"power"`
+	Mode        FanMode    `json:"mode"`
+	FanSpeed    int        `json:"fanSpeed"`
+	Oscillation PowerState `json:"oscillation"`
+	// VerticalOscillation is only reported by fans that can tilt.
+	VerticalOscillation PowerState `json:"verticalOscillation,omitempty"`
+	NightStatus         int        `json:"nightStatus,omitempty"`
+	ChargingStatus      string     `json:"chargingStatus,omitempty"`
+}
+
+// Settings returns the part of the status FanSetAll sets. Oscillation isn't
+// included: the API has no command to change it.
+func (s *FanStatus) Settings() FanSettings {
+	night := FanNightLightOff
+	if s.NightStatus > 0 {
+		night = FanNightLight(strconv.Itoa(s.NightStatus))
+	}
+	return FanSettings{
+		Power:      s.Power == PowerOn,
+		Mode:       s.Mode,
+		Speed:      s.FanSpeed,
+		NightLight: night,
+	}
+}
+
+// MarshalJSON adds the "type" field UnmarshalStatus uses to restore the
+// status.
+func (s FanStatus) MarshalJSON() ([]byte, error) {
+	type plain FanStatus
+	return marshalStatus(statusKindFan, plain(s))
+}
+
+// FanStatus fetches the status of the Circulator Fan with the given id.
+func (c *Client) FanStatus(ctx context.Context, id string) (*FanStatus, error) {
+	return typedStatus[FanStatus](ctx, c, id)
+}
+
+// FanSettings is the combined state sent to a Circulator Fan by FanSetAll.
+// A zero Mode, Speed or NightLight leaves that setting as it is.
+type FanSettings struct {
+	Power bool
+	Mode  FanMode
+	// Speed runs from FanMinSpeed to FanMaxSpeed.
+	Speed      int
+	NightLight FanNightLight
+}
+
+// Validate checks the mode and night light are known values and the speed is
+// within FanMinSpeed and FanMaxSpeed, for the settings that are set.
+func (s FanSettings) Validate() error {
+	if s.Mode != "" {
+		if err := validateFanMode(s.Mode); err != nil {
+			return err
+		}
+	}
+	if s.Speed != 0 {
+		if err := validateFanSpeed(s.Speed); err != nil {
+			return err
+		}
+	}
+	if s.NightLight != "" {
+		if err := validateFanNightLight(s.NightLight); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FanSetAll applies s to a Circulator Fan. The fan has no combined command,
+// so the documented ones are sent in turn: turnOn, then setWindMode,
+// setWindSpeed and setNightLightMode for the settings that are set. When
+// Power is false only turnOff is sent and the other settings are ignored.
+// The first failure stops the sequence, so earlier commands may have taken
+// effect; nothing is sent if s is invalid.
+func (c *Client) FanSetAll(ctx context.Context, id string, s FanSettings) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	if !s.Power {
+		return c.FanTurnOff(ctx, id)
+	}
+
+	if err := c.FanTurnOn(ctx, id); err != nil {
+		return err
+	}
+	if s.Mode != "" {
+		if err := c.FanSetWindMode(ctx, id, s.Mode); err != nil {
+			return err
+		}
+	}
+	if s.Speed != 0 {
+		if err := c.FanSetWindSpeed(ctx, id, s.Speed); err != nil {
+			return err
+		}
+	}
+	if s.NightLight != "" {
+		return c.FanSetNightLightMode(ctx, id, s.NightLight)
+	}
+	return nil
+}
+
+// FanTurnOn switches a Circulator Fan on.
+func (c *Client) FanTurnOn(ctx context.Context, id string) error {
+	return c.SendCommand(ctx, id, Command{Command: "turnOn", Parameter: DefaultParameter})
+}
+
+// FanTurnOff switches a Circulator Fan off.
+func (c *Client) FanTurnOff(ctx context.Context, id string) error {
+	return c.SendCommand(ctx, id, Command{Command: "turnOff", Parameter: DefaultParameter})
+}
+
+// FanSetWindMode sets a Circulator Fan's wind mode.
+func (c *Client) FanSetWindMode(ctx context.Context, id string, mode FanMode) error {
+	if err := validateFanMode(mode); err != nil {
+		return err
+	}
+	return c.SendCommand(ctx, id, Command{Command: "setWindMode", Parameter: string(mode)})
+}
+
+// FanSetWindSpeed sets a Circulator Fan's speed, between FanMinSpeed and
+// FanMaxSpeed.
+func (c *Client) FanSetWindSpeed(ctx context.Context, id string, speed int) error {
+	if err := validateFanSpeed(speed); err != nil {
+		return err
+	}
+	return c.SendCommand(ctx, id, Command{Command: "setWindSpeed", Parameter: strconv.Itoa(speed)})
+}
+
+// FanSetNightLightMode sets a Circulator Fan's night light.
+func (c *Client) FanSetNightLightMode(ctx context.Context, id string, night FanNightLight) error {
+	if err := validateFanNightLight(night); err != nil {
+		return err
+	}
+	return c.SendCommand(ctx, id, Command{Command: "setNightLightMode", Parameter: string(night)})
+}
+
+func validateFanMode(mode FanMode) error {
+	switch mode {
+	case FanModeDirect, FanModeNatural, FanModeSleep, FanModeBaby:
+		return nil
+	}
+	return fmt.Errorf("%w: unknown fan mode %q", ErrInvalidParameter, mode)
+}
+
+func validateFanSpeed(speed int) error {
+	if speed < FanMinSpeed || speed > FanMaxSpeed {
+		return fmt.Errorf("%w: fan speed must be between %d and %d, got %d", ErrInvalidParameter, FanMinSpeed, FanMaxSpeed, speed)
+	}
+	return nil
+}
+
+func validateFanNightLight(night FanNightLight) error {
+	switch night {
+	case FanNightLightOff, FanNightLight1, FanNightLight2:
+		return nil
+	}
+	return fmt.Errorf("%w: unknown fan night light %q", ErrInvalidParameter, night)
+}
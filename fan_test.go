@@ -0,0 +1,67 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestFanSetAll(t *testing.T) {
+	c, srv := newCommandClient(t)
+	s := FanSettings{Power: true, Mode: FanModeDirect, Speed: 60, NightLight: FanNightLightOff}
+	if err := c.FanSetAll(context.Background(), "DEV000000012", s); err != nil {
+		t.Fatal(err)
+	}
+	wantCommands(t, srv.commands(),
+		sentCommand{"DEV000000012", Command{"turnOn", DefaultParameter, CommandTypeCommand}},
+		sentCommand{"DEV000000012", Command{"setWindMode", "direct", CommandTypeCommand}},
+		sentCommand{"DEV000000012", Command{"setWindSpeed", "60", CommandTypeCommand}},
+		sentCommand{"DEV000000012", Command{"setNightLightMode", "off", CommandTypeCommand}},
+	)
+}
+
+func TestFanSetAllPartial(t *testing.T) {
+	c, srv := newCommandClient(t)
+	if err := c.FanSetAll(context.Background(), "DEV000000012", FanSettings{Power: true, Speed: 25}); err != nil {
+		t.Fatal(err)
+	}
+	wantCommands(t, srv.commands(),
+		sentCommand{"DEV000000012", Command{"turnOn", DefaultParameter, CommandTypeCommand}},
+		sentCommand{"DEV000000012", Command{"setWindSpeed", "25", CommandTypeCommand}},
+	)
+}
+
+func TestFanSetAllOff(t *testing.T) {
+	c, srv := newCommandClient(t)
+	if err := c.FanSetAll(context.Background(), "DEV000000012", FanSettings{Mode: FanModeSleep, Speed: 10}); err != nil {
+		t.Fatal(err)
+	}
+	wantCommands(t, srv.commands(), sentCommand{"DEV000000012", Command{"turnOff", DefaultParameter, CommandTypeCommand}})
+}
+
+func TestFanSettingsValidate(t *testing.T) {
+	invalid := map[string]FanSettings{
+		"unknown mode":        {Power: true, Mode: "turbo"},
+		"speed too low":       {Power: true, Speed: -1},
+		"speed too high":      {Power: true, Speed: 101},
+		"unknown night light": {Power: true, NightLight: "3"},
+	}
+	c, srv := newCommandClient(t)
+	for name, s := range invalid {
+		if err := c.FanSetAll(context.Background(), "DEV000000012", s); !errors.Is(err, ErrInvalidParameter) {
+			t.Errorf("%s: err = %v, want ErrInvalidParameter", name, err)
+		}
+	}
+	wantCommands(t, srv.commands())
+}
+
+func TestFanStatusSettings(t *testing.T) {
+	s := &FanStatus{Power: PowerOn, Mode: FanModeNatural, FanSpeed: 40, Oscillation: PowerOn, NightStatus: 2}
+	want := FanSettings{Power: true, Mode: FanModeNatural, Speed: 40, NightLight: FanNightLight2}
+	if got := s.Settings(); got != want {
+		t.Errorf("Settings() = %+v, want %+v", got, want)
+	}
+	if err := s.Settings().Validate(); err != nil {
+		t.Errorf("settings from a status don't validate: %v", err)
+	}
+}
@@ -0,0 +1,201 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// statusFixtures is what each testdata/status_*.json fixture decodes to.
+var statusFixtures = map[string]any{
+	"status_air_purifier": &AirPurifierStatus{
+		BaseStatus: BaseStatus{"DEV000000010", DeviceTypeAirPurifierVOC, "HUB000000001"},
+		Version:    "V1.2", Power: "ON", Mode: 2,
+	},
+	"status_blind_tilt": &BlindTiltStatus{
+		BaseStatus: BaseStatus{"DEV000000011", DeviceTypeBlindTilt, "HUB000000001"},
+		Version:    "V2.3", Calibrate: true, Direction: "up", SlidePosition: 60, Battery: 87,
+	},
+	"status_bot": &BotStatus{
+		BaseStatus: BaseStatus{"DEV000000001", DeviceTypeBot, "HUB000000001"},
+		Version:    "V6.3", Power: PowerOff, Battery: 100, DeviceMode: "switchMode",
+	},
+	"status_ceiling_light": &LightStatus{
+		BaseStatus: BaseStatus{"DEV000000015", DeviceTypeCeilingLight, "HUB000000001"},
+		Version:    "V1.1", Power: PowerOff, Brightness: 100, ColorTemperature: 4000,
+	},
+	"status_circulator_fan": &FanStatus{
+		BaseStatus: BaseStatus{"DEV000000012", DeviceTypeCirculatorFan, "HUB000000001"},
+		Version:    "V3.1", Battery: 62, Power: PowerOn, Mode: FanModeNatural, FanSpeed: 40,
+		Oscillation: PowerOn, VerticalOscillation: PowerOff, ChargingStatus: "uncharged",
+	},
+	"status_color_bulb": &LightStatus{
+		BaseStatus: BaseStatus{"DEV000000014", DeviceTypeColorBulb, "HUB000000001"},
+		Version:    "V1.6", Power: PowerOn, Brightness: 80, Color: "255:128:0", ColorTemperature: 2700,
+	},
+	"status_contact": &ContactStatus{
+		BaseStatus: BaseStatus{"DEV000000028", DeviceTypeContactSensor, "HUB000000001"},
+		Version:    "V1.5", Battery: 90, OpenState: ContactTimeOutNotClose, Brightness: BrightnessBright,
+	},
+	"status_curtain": &CurtainStatus{
+		BaseStatus: BaseStatus{"DEV000000002", DeviceTypeCurtain, "HUB000000001"},
+		Version:    "V4.2", Calibrate: true, Battery: 74,
+	},
+	"status_curtain3": &CurtainStatus{
+		BaseStatus: BaseStatus{"DEV000000020", DeviceTypeCurtain3, "HUB000000001"},
+		Version:    "V3.3", Calibrate: true, Battery: 100, SlidePosition: 35, LightLevel: "bright",
+	},
+	"status_curtain_uncalibrated": &CurtainStatus{
+		BaseStatus: BaseStatus{"DEV000000023", DeviceTypeCurtain, "HUB000000001"},
+		Version:    "V4.2", Battery: 66,
+	},
+	"status_floor_lamp": &LightStatus{
+		BaseStatus: BaseStatus{"DEV000000022", DeviceTypeFloorLamp, "HUB000000001"},
+		Version:    "V1.1", Power: PowerOff, Brightness: 100, Color: "255:255:255", ColorTemperature: 3000,
+	},
+	"status_hub2": &HubStatus{
+		BaseStatus: BaseStatus{"HUB000000001", DeviceTypeHub2, "000000000000"},
+		Version:    "V1.0-1.0", Temperature: flexFloatPtr(22.1), Humidity: flexIntPtr(47), LightLevel: intPtr(12),
+	},
+	"status_humidifier": &HumidifierStatus{
+		BaseStatus: BaseStatus{"DEV000000013", DeviceTypeHumidifier, "HUB000000001"},
+		Power:      PowerOn, Humidity: 48, Temperature: 22.5, Sound: true, LackWater: true, NebulizationEfficiency: intPtr(60),
+	},
+	"status_keypad": &KeypadStatus{
+		BaseStatus: BaseStatus{"DEV000000018", DeviceTypeKeypadTouch, "HUB000000001"},
+		Version:    "V1.2", Battery: 80,
+	},
+	"status_lock": &LockStatus{
+		BaseStatus: BaseStatus{"DEV000000016", DeviceTypeLock, "HUB000000001"},
+		Version:    "V5.4", Battery: 91, Calibrate: true, LockState: "locked", DoorState: "closed",
+	},
+	"status_lock_pro": &LockStatus{
+		BaseStatus: BaseStatus{"DEV000000030", DeviceTypeLockPro, "HUB000000001"},
+		Version:    "V1.3", Battery: 64, Calibrate: true, LockState: LockStateJammed, DoorState: DoorOpened,
+	},
+	"status_meter": &MeterStatus{
+		BaseStatus: BaseStatus{"DEV000000004", DeviceTypeMeterPlus, "HUB000000001"},
+		Version:    "V2.5", Temperature: 21.4, Humidity: 52, Battery: 95,
+	},
+	"status_meter_no_battery": &MeterStatus{
+		BaseStatus: BaseStatus{"DEV000000032", DeviceTypeMeter, "HUB000000001"},
+		Version:    "V2.0", Temperature: 20.6, Humidity: 49, RSSI: -72,
+	},
+	"status_meter_pro": &MeterStatus{
+		BaseStatus: BaseStatus{"DEV000000025", DeviceTypeMeterPro, "HUB000000001"},
+		Version:    "V1.2", Temperature: 19.8, Humidity: 57, Battery: 100,
+	},
+	"status_meter_pro_co2": &MeterStatus{
+		BaseStatus: BaseStatus{"DEV000000024", DeviceTypeMeterProCO2, "HUB000000001"},
+		Version:    "V1.2", Temperature: 23.1, Humidity: 44, Battery: 88, CO2: intPtr(1120),
+	},
+	"status_meter_strings": &MeterStatus{
+		BaseStatus: BaseStatus{"DEV000000029", DeviceTypeMeter, "HUB000000001"},
+		Version:    "V2.1", Temperature: 22.5, Humidity: 52, Battery: 77,
+	},
+	"status_motion": &MotionStatus{
+		BaseStatus: BaseStatus{"DEV000000027", DeviceTypeMotionSensor, "HUB000000001"},
+		Version:    "V1.3", Battery: 80, MoveDetected: true, Brightness: BrightnessDim,
+	},
+	"status_outdoor_meter": &MeterStatus{
+		BaseStatus: BaseStatus{"DEV000000031", DeviceTypeOutdoorMeter, "HUB000000001"},
+		Version:    "V1.4", Temperature: 8.3, Humidity: 81, Battery: 85, RSSI: -67,
+	},
+	"status_plug": &PlugStatus{
+		BaseStatus: BaseStatus{"DEV000000003", DeviceTypePlugMiniUS, ""},
+		Version:    "V1.4", Power: PowerOn, Voltage: 120.3, Weight: 42, ElectricityOfDay: 95, ElectricCurrent: 350,
+	},
+	"status_strip_light3": &LightStatus{
+		BaseStatus: BaseStatus{"DEV000000021", DeviceTypeStripLight3, "HUB000000001"},
+		Version:    "V1.0", Power: PowerOn, Brightness: 60, Color: "0:128:255", ColorTemperature: 4000,
+	},
+	"status_vacuum": &VacuumStatus{
+		BaseStatus:    BaseStatus{"DEV000000019", DeviceTypeVacuumK10Plus, ""},
+		WorkingStatus: "Charging", OnlineStatus: "online", Battery: 100,
+	},
+	"status_water_leak_dry": &WaterLeakStatus{
+		BaseStatus: BaseStatus{"DEV000000017", DeviceTypeWaterLeakDetector, "HUB000000001"},
+		Version:    "V1.0", Battery: 100, Status: WaterDry,
+	},
+	"status_water_leak_leak": &WaterLeakStatus{
+		BaseStatus: BaseStatus{"DEV000000017", DeviceTypeWaterLeakDetector, "HUB000000001"},
+		Version:    "V1.0", Battery: 98, Status: WaterLeak,
+	},
+}
+
+func flexFloatPtr(f FlexFloat) *FlexFloat { return &f }
+func flexIntPtr(n FlexInt) *FlexInt       { return &n }
+
+// TestStatusFixtures decodes every recorded status strictly, so a fixture
+// with a field the typed status doesn't model fails.
+func TestStatusFixtures(t *testing.T) {
+	files, err := filepath.Glob("testdata/status_*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != len(statusFixtures) {
+		t.Errorf("%d status fixtures on disk, %d in statusFixtures", len(files), len(statusFixtures))
+	}
+
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".json")
+		want, ok := statusFixtures[name]
+		if !ok {
+			t.Errorf("%s: no expected status in statusFixtures", name)
+			continue
+		}
+
+		c := newTestClient(t, serveFixture(t, name), WithStrictDecoding(true))
+		got, err := c.DeviceStatus(context.Background(), "ANY")
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: decoded %+v, want %+v", name, got, want)
+		}
+	}
+}
+
+func TestDeviceListFixtures(t *testing.T) {
+	for _, name := range []string{"devices", "devices_old_account", "devices_no_status_code"} {
+		c := newTestClient(t, serveFixture(t, name), WithStrictDecoding(true))
+		devices, err := c.Devices(context.Background())
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		for _, d := range devices {
+			if d.DeviceID == "" || d.DeviceType == "" {
+				t.Errorf("%s: device without id or type: %+v", name, d)
+			}
+		}
+	}
+}
+
+func TestEndpointFixtures(t *testing.T) {
+	ctx := context.Background()
+
+	scenes, err := newTestClient(t, serveFixture(t, "scenes"), WithStrictDecoding(true)).Scenes(ctx)
+	if err != nil || len(scenes) != 1 || scenes[0].SceneName != "Good night" {
+		t.Errorf("scenes = %+v, %v", scenes, err)
+	}
+
+	if err := newTestClient(t, serveFixture(t, "command")).SendCommand(ctx, "DEV000000001", Command{Command: "press"}); err != nil {
+		t.Errorf("command: %v", err)
+	}
+
+	err = newTestClient(t, serveFixture(t, "error_device_offline")).SendCommand(ctx, "DEV000000001", Command{Command: "press"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 161 || apiErr.Message != "device offline" {
+		t.Errorf("offline device: err = %v, want API error 161", err)
+	}
+
+	urls, err := newTestClient(t, serveFixture(t, "webhook_query_url")).webhookURLs(ctx)
+	if err != nil || len(urls) != 1 || urls[0] != "https://example.com/switchbot/webhook" {
+		t.Errorf("webhook urls = %v, %v", urls, err)
+	}
+}
@@ -0,0 +1,149 @@
+package switchbot
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const (
+	testToken  = "test-token-0123456789abcdef"
+	testSecret = "test-secret-0123456789abcdef"
+)
+
+// newTestClient starts a server running h and returns a client pointed at it.
+func newTestClient(t *testing.T, h http.HandlerFunc, opts ...Option) *Client {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	c, err := NewClient(testToken, testSecret, append([]Option{WithBaseURL(srv.URL)}, opts...)...)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	return c
+}
+
+// writeEnvelope writes a SwitchBot response envelope carrying body.
+func writeEnvelope(w http.ResponseWriter, statusCode int, body any) {
+	b, err := json.Marshal(body)
+	if err != nil {
+		panic(err)
+	}
+	json.NewEncoder(w).Encode(map[string]any{
+		"statusCode": statusCode,
+		"message":    "success",
+		"body":       json.RawMessage(b),
+	})
+}
+
+// success answers every request with an empty successful envelope.
+func success(w http.ResponseWriter, _ *http.Request) {
+	writeEnvelope(w, statusSuccess, struct{}{})
+}
+
+// sentCommand is a command received by a commandServer.
+type sentCommand struct {
+	DeviceID string
+	Command
+}
+
+// commandServer records the commands posted to it and answers them
+// successfully. GET /devices is answered with devices and remotes; other
+// requests get an empty successful envelope.
+type commandServer struct {
+	devices []Device
+	remotes []InfraredRemote
+
+	mu   sync.Mutex
+	sent []sentCommand
+}
+
+func (s *commandServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == apiVersion+"/devices" {
+		writeEnvelope(w, statusSuccess, deviceList{DeviceList: s.devices, InfraredRemoteList: s.remotes})
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, apiVersion+"/devices/")
+	if id, ok := strings.CutSuffix(path, "/commands"); ok && r.Method == http.MethodPost {
+		var cmd Command
+		if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.mu.Lock()
+		s.sent = append(s.sent, sentCommand{DeviceID: id, Command: cmd})
+		s.mu.Unlock()
+	}
+	success(w, r)
+}
+
+// commands returns the commands received so far.
+func (s *commandServer) commands() []sentCommand {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]sentCommand(nil), s.sent...)
+}
+
+// newCommandClient returns a client pointed at a new commandServer with no
+// devices.
+func newCommandClient(t *testing.T, opts ...Option) (*Client, *commandServer) {
+	t.Helper()
+	return newDeviceClient(t, nil, nil, opts...)
+}
+
+// newDeviceClient returns a client pointed at a new commandServer listing
+// devices and remotes.
+func newDeviceClient(t *testing.T, devices []Device, remotes []InfraredRemote, opts ...Option) (*Client, *commandServer) {
+	t.Helper()
+	s := &commandServer{devices: devices, remotes: remotes}
+	return newTestClient(t, s.ServeHTTP, opts...), s
+}
+
+// wantCommands fails the test unless got holds exactly want, in order. The
+// parameters compared must be strings.
+func wantCommands(t *testing.T, got []sentCommand, want ...sentCommand) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("sent %d commands %v, want %d %v", len(got), got, len(want), want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("command %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+// writeAPIError writes an envelope failing with statusCode.
+func writeAPIError(w http.ResponseWriter, statusCode int, message string) {
+	json.NewEncoder(w).Encode(map[string]any{
+		"statusCode": statusCode,
+		"message":    message,
+		"body":       struct{}{},
+	})
+}
+
+// loadFixture returns the recorded response testdata/<name>.json.
+func loadFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join("testdata", name+".json"))
+	if err != nil {
+		t.Fatalf("loading fixture: %v", err)
+	}
+	return b
+}
+
+// serveFixture answers every request with the recorded response
+// testdata/<name>.json.
+func serveFixture(t *testing.T, name string) http.HandlerFunc {
+	body := loadFixture(t, name)
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}
+}
@@ -0,0 +1,61 @@
+package switchbot
+
+import "context"
+
+// HubStatus is the status of a Hub Mini, Hub Plus or Hub 2.
+//
+// Only the Hub 2 has sensors, so Temperature, Humidity and LightLevel are
+// nil on other hubs. SwitchBot reports neither signal strength nor the
+// number of connected devices for hubs; use HubDevices for the latter.
+type HubStatus struct {
+	BaseStatus
+	Version string `json:"version,omitempty"`
+
+	// Temperature is in degrees Celsius and Humidity in percent.
+	Temperature *FlexFloat `json:"temperature,omitempty"`
+	Humidity    *FlexInt   `json:"humidity,omitempty"`
+	// LightLevel is the ambient light on a 1–20 scale.
+	LightLevel *int `json:"lightLevel,omitempty"`
+}
+
+// MarshalJSON adds the "type" field UnmarshalStatus uses to restore the
+// status.
+func (s HubStatus) MarshalJSON() ([]byte, error) {
+	type plain HubStatus
+	return marshalStatus(statusKindHub, plain(s))
+}
+
+// HubStatus fetches the status of the hub with the given id.
+func (c *Client) HubStatus(ctx context.Context, id string) (*HubStatus, error) {
+	return typedStatus[HubStatus](ctx, c, id)
+}
+
+// HubDevices lists the devices and IR remotes that reach the cloud through
+// the hub with the given id, to see what a weak or offline hub affects. IR
+// remotes are listed with DeviceType set to DeviceTypeInfraredRemote. Both
+// come with their annotations.
+func (c *Client) HubDevices(ctx context.Context, hubID string) ([]Device, error) {
+	list, err := c.deviceList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var devices []Device
+	for _, d := range list.DeviceList {
+		if d.HubDeviceID == hubID && d.DeviceID != hubID {
+			devices = append(devices, d)
+		}
+	}
+	for _, r := range list.InfraredRemoteList {
+		if r.HubDeviceID == hubID {
+			devices = append(devices, Device{
+				DeviceID:    r.DeviceID,
+				DeviceName:  r.DeviceName,
+				DeviceType:  DeviceTypeInfraredRemote,
+				HubDeviceID: r.HubDeviceID,
+			})
+		}
+	}
+	c.annotate(devices)
+	return devices, nil
+}
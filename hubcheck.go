@@ -0,0 +1,56 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrNoHub is returned when a command is sent to a Bluetooth device that
+// isn't paired with a hub, so the cloud can't reach it. SwitchBot reports
+// this as statusCode 171; with WithHubCheck it is caught before sending.
+var ErrNoHub = errors.New("device has no hub")
+
+// statusNoHub is the envelope statusCode for a device without a hub.
+const statusNoHub = 171
+
+// noHubID is the hubDeviceId SwitchBot reports for a device without a hub.
+const noHubID = "000000000000"
+
+// hubTypes lists the Bluetooth device types that are only reachable through
+// a hub. Wi-Fi devices such as plugs, bulbs and hubs report no hub and don't
+// need one.
+var hubTypes = map[DeviceType]bool{
+	DeviceTypeBot:           true,
+	DeviceTypeBlindTilt:     true,
+	DeviceTypeCirculatorFan: true,
+	DeviceTypeCurtain:       true,
+	DeviceTypeCurtain3:      true,
+	DeviceTypeKeypad:        true,
+	DeviceTypeKeypadTouch:   true,
+	DeviceTypeLock:          true,
+	DeviceTypeLockPro:       true,
+}
+
+// checkHub returns an error wrapping ErrNoHub if WithHubCheck is set and the
+// device list shows the device needs a hub but has none. Devices missing
+// from the list, and failures fetching it, are left for the API to judge.
+func (c *Client) checkHub(ctx context.Context, id string) error {
+	if !c.hubCheck {
+		return nil
+	}
+	list, err := c.deviceList(ctx)
+	if err != nil {
+		return nil
+	}
+	for _, d := range list.DeviceList {
+		if d.DeviceID != id {
+			continue
+		}
+		if hubTypes[d.DeviceType] && (d.HubDeviceID == "" || d.HubDeviceID == noHubID) {
+			return fmt.Errorf("%w: %s %q (%s) is not paired with a hub; add it to a hub and enable cloud services in the SwitchBot app", ErrNoHub, d.DeviceType, d.DeviceName, id)
+		}
+		return nil
+	}
+	return nil
+}
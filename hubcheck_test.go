@@ -0,0 +1,67 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestHubCheck(t *testing.T) {
+	devices := []Device{
+		{DeviceID: "BOT01", DeviceName: "Kettle", DeviceType: DeviceTypeBot, HubDeviceID: ""},
+		{DeviceID: "LOCK01", DeviceName: "Front door", DeviceType: DeviceTypeLock, HubDeviceID: noHubID},
+		{DeviceID: "BOT02", DeviceName: "Lights", DeviceType: DeviceTypeBot, HubDeviceID: "HUB01"},
+		{DeviceID: "PLUG01", DeviceName: "Lamp", DeviceType: DeviceTypePlugMiniUS, HubDeviceID: ""},
+	}
+	c, srv := newDeviceClient(t, devices, nil, WithHubCheck(true))
+	ctx := context.Background()
+
+	for _, id := range []string{"BOT01", "LOCK01"} {
+		if err := c.SendCommand(ctx, id, Command{Command: "turnOn"}); !errors.Is(err, ErrNoHub) {
+			t.Errorf("%s: err = %v, want ErrNoHub", id, err)
+		}
+	}
+	if got := srv.commands(); len(got) != 0 {
+		t.Fatalf("sent %v to devices without a hub, want nothing sent", got)
+	}
+
+	for _, id := range []string{"BOT02", "PLUG01", "UNLISTED"} {
+		if err := c.SendCommand(ctx, id, Command{Command: "turnOn"}); err != nil {
+			t.Errorf("%s: %v", id, err)
+		}
+	}
+	if got := srv.commands(); len(got) != 3 {
+		t.Errorf("sent %v, want the commands to the bot with a hub, the plug and the unlisted device", got)
+	}
+}
+
+func TestHubCheckDisabled(t *testing.T) {
+	devices := []Device{{DeviceID: "BOT01", DeviceType: DeviceTypeBot}}
+	c, srv := newDeviceClient(t, devices, nil)
+
+	if err := c.SendCommand(context.Background(), "BOT01", Command{Command: "turnOn"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.commands(); len(got) != 1 {
+		t.Errorf("sent %v, want the command sent without WithHubCheck", got)
+	}
+}
+
+func TestNoHubStatusCode(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeAPIError(w, statusNoHub, "hub is offline or not bound")
+	})
+
+	err := c.SendCommand(context.Background(), "BOT01", Command{Command: "turnOn"})
+	if !errors.Is(err, ErrNoHub) {
+		t.Errorf("err = %v, want ErrNoHub for statusCode 171", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != statusNoHub {
+		t.Errorf("err = %v, want the APIError kept", err)
+	}
+	if errors.Is(&APIError{StatusCode: statusDeviceNotFound}, ErrNoHub) {
+		t.Error("statusCode 152 matches ErrNoHub")
+	}
+}
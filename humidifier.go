@@ -0,0 +1,44 @@
+package switchbot
+
+import "context"
+
+// HumidifierStatus is the status of a Humidifier.
+//
+// The ultrasonic Humidifier reports LackWater and NebulizationEfficiency on
+// current firmware; older firmware omits them, leaving them false and nil.
+type HumidifierStatus struct {
+	BaseStatus
+	Version     string     `json:"version,omitempty"`
+	Power       PowerState `json:"power"`
+	Humidity    FlexInt    `json:"humidity"`
+	Temperature FlexFloat  `json:"temperature"`
+	Auto        bool       `json:"auto"`
+	ChildLock   bool       `json:"childLock"`
+	Sound       bool       `json:"sound"`
+
+	// LackWater is set when the tank is empty.
+	LackWater bool `json:"lackWater,omitempty"`
+	// NebulizationEfficiency is the mist output, 0–100.
+	NebulizationEfficiency *int `json:"nebulizationEfficiency,omitempty"`
+}
+
+// MistLevel returns the mist output as a fraction from 0 to 1, and false if
+// the firmware doesn't report it.
+func (s *HumidifierStatus) MistLevel() (float64, bool) {
+	if s.NebulizationEfficiency == nil {
+		return 0, false
+	}
+	return float64(*s.NebulizationEfficiency) / 100, true
+}
+
+// MarshalJSON adds the "type" field UnmarshalStatus uses to restore the
+// status.
+func (s HumidifierStatus) MarshalJSON() ([]byte, error) {
+	type plain HumidifierStatus
+	return marshalStatus(statusKindHumidifier, plain(s))
+}
+
+// HumidifierStatus fetches the status of the Humidifier with the given id.
+func (c *Client) HumidifierStatus(ctx context.Context, id string) (*HumidifierStatus, error) {
+	return typedStatus[HumidifierStatus](ctx, c, id)
+}
@@ -0,0 +1,28 @@
+package switchbot
+
+import (
+	"context"
+	"testing"
+)
+
+func TestHumidifierStatusFixture(t *testing.T) {
+	c := newTestClient(t, serveFixture(t, "status_humidifier"))
+
+	s, err := c.HumidifierStatus(context.Background(), "DEV000000013")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.LackWater {
+		t.Error("LackWater = false, want true for an empty tank")
+	}
+	if level, ok := s.MistLevel(); !ok || level != 0.6 {
+		t.Errorf("MistLevel = %v, %v, want 0.6, true", level, ok)
+	}
+}
+
+func TestHumidifierMistLevelUnreported(t *testing.T) {
+	s := &HumidifierStatus{}
+	if level, ok := s.MistLevel(); ok || level != 0 {
+		t.Errorf("MistLevel = %v, %v, want 0, false without nebulizationEfficiency", level, ok)
+	}
+}
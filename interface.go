@@ -0,0 +1,18 @@
+package switchbot
+
+import "context"
+
+// SwitchBot is the core of the API as implemented by Client: listing
+// devices and scenes, reading statuses and sending commands. Code that
+// depends on it rather than on *Client can be exercised against the
+// in-memory fake in the switchbottest package.
+type SwitchBot interface {
+	Devices(ctx context.Context) ([]Device, error)
+	InfraredRemotes(ctx context.Context) ([]InfraredRemote, error)
+	DeviceStatus(ctx context.Context, id string) (any, error)
+	SendCommand(ctx context.Context, id string, cmd Command, opts ...CallOption) error
+	Scenes(ctx context.Context) ([]Scene, error)
+	ExecuteScene(ctx context.Context, id string) (*SceneResult, error)
+}
+
+var _ SwitchBot = (*Client)(nil)
@@ -0,0 +1,99 @@
+package switchbot
+
+import (
+	"context"
+	"slices"
+)
+
+// DeviceInventory is a structured view of everything on the account.
+type DeviceInventory struct {
+	Devices         []Device
+	InfraredRemotes []InfraredRemote
+
+	// ByType groups Devices by device type.
+	ByType map[DeviceType][]Device
+	// RemotesByCategory groups InfraredRemotes by normalized remote type,
+	// see NormalizeRemoteType.
+	RemotesByCategory map[string][]InfraredRemote
+}
+
+// DeviceCount is the number of physical devices.
+func (inv *DeviceInventory) DeviceCount() int {
+	return len(inv.Devices)
+}
+
+// RemoteCount is the number of IR remotes.
+func (inv *DeviceInventory) RemoteCount() int {
+	return len(inv.InfraredRemotes)
+}
+
+// CountByType returns how many devices there are of each type.
+func (inv *DeviceInventory) CountByType() map[DeviceType]int {
+	counts := make(map[DeviceType]int, len(inv.ByType))
+	for t, devices := range inv.ByType {
+		counts[t] = len(devices)
+	}
+	return counts
+}
+
+// Inventory fetches the device list and returns it grouped by type, with
+// the devices' and remotes' annotations. Unlike
+// Devices, an account with no devices yields an empty inventory rather than
+// ErrNoDevices.
+func (c *Client) Inventory(ctx context.Context) (*DeviceInventory, error) {
+	list, err := c.deviceList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	inv := &DeviceInventory{
+		Devices:           slices.Clone(list.DeviceList),
+		InfraredRemotes:   slices.Clone(list.InfraredRemoteList),
+		ByType:            make(map[DeviceType][]Device),
+		RemotesByCategory: make(map[string][]InfraredRemote),
+	}
+	c.annotate(inv.Devices)
+	c.annotateRemotes(inv.InfraredRemotes)
+	for _, d := range inv.Devices {
+		inv.ByType[d.DeviceType] = append(inv.ByType[d.DeviceType], d)
+	}
+	for _, r := range inv.InfraredRemotes {
+		category := NormalizeRemoteType(r.RemoteType)
+		inv.RemotesByCategory[category] = append(inv.RemotesByCategory[category], r)
+	}
+	return inv, nil
+}
+
+// DiffInventory compares the live device list with expected, matching
+// devices by id, to detect configuration drift. added and changed hold the
+// live devices, in device list order; removed holds the expected devices no
+// longer present. A device has changed if its name or type differs. IR
+// remotes aren't compared.
+func (c *Client) DiffInventory(ctx context.Context, expected []Device) (added, removed, changed []Device, err error) {
+	list, err := c.deviceList(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	want := make(map[string]Device, len(expected))
+	for _, d := range expected {
+		want[d.DeviceID] = d
+	}
+	live := make(map[string]bool, len(list.DeviceList))
+	for _, d := range list.DeviceList {
+		live[d.DeviceID] = true
+		e, ok := want[d.DeviceID]
+		switch {
+		case !ok:
+			added = append(added, d)
+		case e.DeviceName != d.DeviceName || e.DeviceType != d.DeviceType:
+			changed = append(changed, d)
+		}
+	}
+	for _, d := range expected {
+		if !live[d.DeviceID] {
+			removed = append(removed, d)
+		}
+	}
+	return added, removed, changed, nil
+}
@@ -0,0 +1,110 @@
+package switchbot
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestInventoryFromFixture(t *testing.T) {
+	c := newTestClient(t, serveFixture(t, "devices"))
+
+	inv, err := c.Inventory(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inv.DeviceCount() != 5 || inv.RemoteCount() != 2 {
+		t.Errorf("counts = %d devices, %d remotes, want 5 and 2", inv.DeviceCount(), inv.RemoteCount())
+	}
+	counts := inv.CountByType()
+	for _, typ := range []DeviceType{DeviceTypeBot, DeviceTypeCurtain, DeviceTypePlugMiniUS, DeviceTypeMeterPlus, DeviceTypeHub2} {
+		if counts[typ] != 1 {
+			t.Errorf("CountByType()[%s] = %d, want 1", typ, counts[typ])
+		}
+	}
+	if bots := inv.ByType[DeviceTypeBot]; len(bots) != 1 || bots[0].DeviceID != "DEV000000001" {
+		t.Errorf("ByType[Bot] = %+v", bots)
+	}
+	if tvs := inv.RemotesByCategory["TV"]; len(tvs) != 1 || tvs[0].RemoteType != "DIY TV" {
+		t.Errorf("RemotesByCategory[TV] = %+v, want the DIY TV remote", tvs)
+	}
+}
+
+func TestInventoryGroupsByType(t *testing.T) {
+	devices := []Device{
+		{DeviceID: "DEV000000001", DeviceType: DeviceTypeBot},
+		{DeviceID: "DEV000000003", DeviceType: DeviceTypePlugMiniUS},
+		{DeviceID: "DEV000000007", DeviceType: DeviceTypeBot},
+	}
+	c, _ := newDeviceClient(t, devices, nil)
+
+	inv, err := c.Inventory(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	bots := inv.ByType[DeviceTypeBot]
+	if len(bots) != 2 || bots[0].DeviceID != "DEV000000001" || bots[1].DeviceID != "DEV000000007" {
+		t.Errorf("ByType[Bot] = %+v, want both bots in list order", bots)
+	}
+}
+
+func TestInventoryEmptyAccount(t *testing.T) {
+	c, _ := newDeviceClient(t, nil, nil)
+	inv, err := c.Inventory(context.Background())
+	if err != nil {
+		t.Fatalf("err = %v, want an empty inventory", err)
+	}
+	if inv.DeviceCount() != 0 || inv.RemoteCount() != 0 {
+		t.Errorf("inventory = %+v, want empty", inv)
+	}
+}
+
+func TestDiffInventory(t *testing.T) {
+	live := []Device{
+		{DeviceID: "bot", DeviceName: "Kettle", DeviceType: DeviceTypeBot},
+		{DeviceID: "plug", DeviceName: "Desk lamp", DeviceType: DeviceTypePlug},
+		{DeviceID: "lock", DeviceName: "Front door", DeviceType: DeviceTypeLockPro},
+		{DeviceID: "new", DeviceName: "Hallway", DeviceType: DeviceTypeMeter},
+	}
+	expected := []Device{
+		{DeviceID: "bot", DeviceName: "Kettle", DeviceType: DeviceTypeBot},
+		{DeviceID: "plug", DeviceName: "Lamp", DeviceType: DeviceTypePlug},
+		{DeviceID: "lock", DeviceName: "Front door", DeviceType: DeviceTypeLock},
+		{DeviceID: "gone", DeviceName: "Garage", DeviceType: DeviceTypeCurtain},
+	}
+	c, _ := newDeviceClient(t, live, []InfraredRemote{{DeviceID: "tv", DeviceName: "TV"}})
+
+	added, removed, changed, err := c.DiffInventory(context.Background(), expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := func(devices []Device) []string {
+		var out []string
+		for _, d := range devices {
+			out = append(out, d.DeviceID)
+		}
+		return out
+	}
+	if got := ids(added); !slices.Equal(got, []string{"new"}) {
+		t.Errorf("added = %v, want [new]", got)
+	}
+	if got := ids(removed); !slices.Equal(got, []string{"gone"}) {
+		t.Errorf("removed = %v, want [gone]", got)
+	}
+	if got := ids(changed); !slices.Equal(got, []string{"plug", "lock"}) {
+		t.Errorf("changed = %v, want [plug lock]", got)
+	}
+	if changed[0].DeviceName != "Desk lamp" {
+		t.Errorf("changed holds %+v, want the live device", changed[0])
+	}
+}
+
+func TestDiffInventoryError(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeAPIError(w, 190, "internal error")
+	})
+	if _, _, _, err := c.DiffInventory(context.Background(), nil); err == nil {
+		t.Error("DiffInventory succeeded on a failing device list")
+	}
+}
@@ -0,0 +1,86 @@
+package switchbot
+
+import (
+	"context"
+	"slices"
+)
+
+// PageFunc fetches one page of a list. pageToken is "" for the first page;
+// an empty nextToken marks the last page.
+type PageFunc[T any] func(ctx context.Context, pageToken string) (items []T, nextToken string, err error)
+
+// Iterator walks a list page by page, fetching the next page only when the
+// current one is used up. None of SwitchBot's list endpoints is paginated
+// today; the iterator gives list helpers one consistent shape should that
+// change.
+//
+//	it := c.DevicesIter(ctx)
+//	for it.Next() {
+//		d := it.Value()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator[T any] struct {
+	ctx   context.Context
+	fetch PageFunc[T]
+
+	page    []T
+	pos     int
+	token   string
+	started bool
+	value   T
+	err     error
+}
+
+// NewIterator returns an Iterator over the pages returned by fetch.
+func NewIterator[T any](ctx context.Context, fetch PageFunc[T]) *Iterator[T] {
+	return &Iterator[T]{ctx: ctx, fetch: fetch}
+}
+
+// Next advances to the next item, fetching a page if needed. It returns false
+// at the end of the list or on error; check Err to tell them apart.
+func (it *Iterator[T]) Next() bool {
+	for it.pos >= len(it.page) {
+		if it.err != nil || (it.started && it.token == "") {
+			return false
+		}
+		page, next, err := it.fetch(it.ctx, it.token)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.started = true
+		it.page, it.pos, it.token = page, 0, next
+	}
+
+	it.value = it.page[it.pos]
+	it.pos++
+	return true
+}
+
+// Value returns the current item.
+func (it *Iterator[T]) Value() T {
+	return it.value
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// DevicesIter is Devices as an Iterator, with the devices' annotations. An
+// account without devices yields an empty iteration rather than
+// ErrNoDevices.
+func (c *Client) DevicesIter(ctx context.Context) *Iterator[Device] {
+	return NewIterator(ctx, func(ctx context.Context, _ string) ([]Device, string, error) {
+		list, err := c.deviceList(ctx)
+		if err != nil {
+			return nil, "", err
+		}
+		devices := slices.Clone(list.DeviceList)
+		c.annotate(devices)
+		return devices, "", nil
+	})
+}
@@ -0,0 +1,98 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+// pages is a fake paginated source: page i is served for token str(i), and
+// the page at failAt, if any, fails.
+func pages(fetched *[]string, failAt int, items ...[]int) PageFunc[int] {
+	return func(_ context.Context, token string) ([]int, string, error) {
+		*fetched = append(*fetched, token)
+		i := 0
+		if token != "" {
+			i = int(token[0] - '0')
+		}
+		if i == failAt {
+			return nil, "", errors.New("page unavailable")
+		}
+		next := ""
+		if i+1 < len(items) {
+			next = string(rune('0' + i + 1))
+		}
+		return items[i], next, nil
+	}
+}
+
+func collect[T any](it *Iterator[T]) []T {
+	var out []T
+	for it.Next() {
+		out = append(out, it.Value())
+	}
+	return out
+}
+
+func TestIteratorPages(t *testing.T) {
+	var fetched []string
+	it := NewIterator(context.Background(), pages(&fetched, -1, []int{1, 2}, nil, []int{3}, []int{4, 5}))
+
+	if got := collect(it); !slices.Equal(got, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("items = %v, want 1 to 5", got)
+	}
+	if err := it.Err(); err != nil {
+		t.Errorf("Err = %v", err)
+	}
+	if !slices.Equal(fetched, []string{"", "1", "2", "3"}) {
+		t.Errorf("fetched pages %q, want each once in order", fetched)
+	}
+	if it.Next() {
+		t.Error("Next after the end returned true")
+	}
+}
+
+func TestIteratorFetchesLazily(t *testing.T) {
+	var fetched []string
+	it := NewIterator(context.Background(), pages(&fetched, -1, []int{1, 2}, []int{3}))
+	it.Next()
+	it.Next()
+	if len(fetched) != 1 {
+		t.Errorf("fetched %d pages before the first was used up, want 1", len(fetched))
+	}
+}
+
+func TestIteratorError(t *testing.T) {
+	var fetched []string
+	it := NewIterator(context.Background(), pages(&fetched, 1, []int{1, 2}, []int{3}))
+
+	if got := collect(it); !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("items before the error = %v", got)
+	}
+	if it.Err() == nil {
+		t.Fatal("Err = nil after a failed page")
+	}
+	if it.Next() || len(fetched) != 2 {
+		t.Errorf("iteration continued after the error, fetched %q", fetched)
+	}
+}
+
+func TestDevicesIter(t *testing.T) {
+	devices := []Device{{DeviceID: "DEV000000001"}, {DeviceID: "DEV000000002"}}
+	c, _ := newDeviceClient(t, devices, nil)
+
+	var ids []string
+	it := c.DevicesIter(context.Background())
+	for it.Next() {
+		ids = append(ids, it.Value().DeviceID)
+	}
+	if it.Err() != nil || !slices.Equal(ids, []string{"DEV000000001", "DEV000000002"}) {
+		t.Errorf("ids = %v, err %v", ids, it.Err())
+	}
+
+	empty, _ := newDeviceClient(t, nil, nil)
+	if got := collect(empty.DevicesIter(context.Background())); len(got) != 0 {
+		t.Errorf("empty account iterated %v", got)
+	}
+}
@@ -0,0 +1,26 @@
+package switchbot
+
+import "context"
+
+// KeypadStatus is the status of a Keypad or Keypad Touch. The lock a keypad
+// is paired with is reported in the device list, as Device.LockDeviceID.
+//
+// SwitchBot's status body for keypads is sparse; Version and Battery are only
+// present on newer firmware.
+type KeypadStatus struct {
+	BaseStatus
+	Version string  `json:"version,omitempty"`
+	Battery Battery `json:"battery,omitempty"`
+}
+
+// MarshalJSON adds the "type" field UnmarshalStatus uses to restore the
+// status.
+func (s KeypadStatus) MarshalJSON() ([]byte, error) {
+	type plain KeypadStatus
+	return marshalStatus(statusKindKeypad, plain(s))
+}
+
+// KeypadStatus fetches the status of the Keypad with the given id.
+func (c *Client) KeypadStatus(ctx context.Context, id string) (*KeypadStatus, error) {
+	return typedStatus[KeypadStatus](ctx, c, id)
+}
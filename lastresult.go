@@ -0,0 +1,86 @@
+package switchbot
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultCommandResultCapacity is the number of devices whose last command
+// is remembered by WithCommandResults(0).
+const DefaultCommandResultCapacity = 1024
+
+// CommandRecord is the outcome of the last command sent to a device, as
+// returned by LastCommandResult.
+type CommandRecord struct {
+	Command Command
+	// Err is nil if the command was accepted.
+	Err error
+	At  time.Time
+}
+
+// resultStore remembers the last CommandRecord per device, dropping the
+// oldest once capacity devices are stored. A nil *resultStore stores nothing.
+type resultStore struct {
+	mu       sync.Mutex
+	capacity int
+	records  map[string]CommandRecord
+}
+
+func newResultStore(capacity int) *resultStore {
+	if capacity < 1 {
+		capacity = DefaultCommandResultCapacity
+	}
+	return &resultStore{capacity: capacity, records: make(map[string]CommandRecord)}
+}
+
+func (s *resultStore) put(id string, r CommandRecord) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.records[id]; !ok && len(s.records) >= s.capacity {
+		var oldest string
+		var oldestAt time.Time
+		for k, rec := range s.records {
+			if oldestAt.IsZero() || rec.At.Before(oldestAt) {
+				oldest, oldestAt = k, rec.At
+			}
+		}
+		delete(s.records, oldest)
+	}
+	s.records[id] = r
+}
+
+func (s *resultStore) get(id string) (CommandRecord, bool) {
+	if s == nil {
+		return CommandRecord{}, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.records[id]
+	return r, ok
+}
+
+func (s *resultStore) clear(id string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, id)
+}
+
+// LastCommandResult returns the outcome of the last command sent to the
+// device with SendCommand or a helper built on it, e.g. to show "last action
+// failed" without querying the device. It needs WithCommandResults and
+// otherwise always reports false.
+func (c *Client) LastCommandResult(id string) (CommandRecord, bool) {
+	return c.results.get(id)
+}
+
+// ClearCommandResult forgets the last command result of the device.
+func (c *Client) ClearCommandResult(id string) {
+	c.results.clear(id)
+}
@@ -0,0 +1,89 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLastCommandResult(t *testing.T) {
+	h := func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/OFFLINE/") {
+			writeAPIError(w, 161, "device offline")
+			return
+		}
+		success(w, r)
+	}
+	c := newTestClient(t, h, WithCommandResults(0))
+	ctx := context.Background()
+
+	sendErr := c.SendCommand(ctx, "OFFLINE", Command{Command: "turnOn"})
+	if sendErr == nil {
+		t.Fatal("expected the command to fail")
+	}
+	rec, ok := c.LastCommandResult("OFFLINE")
+	if !ok {
+		t.Fatal("no result recorded for the failed command")
+	}
+	var apiErr *APIError
+	if !errors.As(rec.Err, &apiErr) || apiErr.StatusCode != 161 || rec.Err != sendErr {
+		t.Errorf("recorded error = %v, want the one SendCommand returned", rec.Err)
+	}
+	if rec.Command.Command != "turnOn" || rec.Command.Parameter != DefaultParameter || rec.At.IsZero() {
+		t.Errorf("record = %+v, want the normalized command and a time", rec)
+	}
+
+	if err := c.SendCommand(ctx, "OFFLINE", Command{Command: "turnOff"}); err == nil {
+		t.Fatal("expected the command to fail")
+	}
+	if rec, _ := c.LastCommandResult("OFFLINE"); rec.Command.Command != "turnOff" {
+		t.Errorf("record = %+v, want the latest command", rec)
+	}
+
+	if err := c.SendCommand(ctx, "BOT01", Command{Command: "press"}); err != nil {
+		t.Fatal(err)
+	}
+	if rec, ok := c.LastCommandResult("BOT01"); !ok || rec.Err != nil {
+		t.Errorf("successful command: record = %+v, %v", rec, ok)
+	}
+
+	c.ClearCommandResult("OFFLINE")
+	if _, ok := c.LastCommandResult("OFFLINE"); ok {
+		t.Error("result still recorded after ClearCommandResult")
+	}
+}
+
+func TestLastCommandResultDisabled(t *testing.T) {
+	c, _ := newCommandClient(t)
+	if err := c.SendCommand(context.Background(), "BOT01", Command{Command: "press"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.LastCommandResult("BOT01"); ok {
+		t.Error("result recorded without WithCommandResults")
+	}
+	c.ClearCommandResult("BOT01")
+}
+
+func TestResultStoreBounded(t *testing.T) {
+	s := newResultStore(2)
+	start := time.Now()
+	s.put("a", CommandRecord{At: start})
+	s.put("b", CommandRecord{At: start.Add(time.Second)})
+	s.put("c", CommandRecord{At: start.Add(2 * time.Second)})
+	if _, ok := s.get("a"); ok {
+		t.Error("oldest record kept beyond capacity")
+	}
+	for _, id := range []string{"b", "c"} {
+		if _, ok := s.get(id); !ok {
+			t.Errorf("record %s dropped", id)
+		}
+	}
+	// Replacing a stored device doesn't evict another
+	s.put("b", CommandRecord{At: start.Add(3 * time.Second)})
+	if _, ok := s.get("c"); !ok {
+		t.Error("updating b evicted c")
+	}
+}
@@ -0,0 +1,106 @@
+package switchbot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLightTurnOnAt(t *testing.T) {
+	c, srv := newCommandClient(t)
+	if err := c.LightTurnOnAt(context.Background(), "BULB01", 40); err != nil {
+		t.Fatal(err)
+	}
+	wantCommands(t, srv.commands(),
+		sentCommand{"BULB01", Command{"setBrightness", "40", CommandTypeCommand}},
+		sentCommand{"BULB01", Command{"turnOn", DefaultParameter, CommandTypeCommand}},
+	)
+}
+
+func TestLightTurnOnAtInvalid(t *testing.T) {
+	c, srv := newCommandClient(t)
+	for _, b := range []int{LightMinBrightness - 1, LightMaxBrightness + 1} {
+		if err := c.LightTurnOnAt(context.Background(), "BULB01", b); !errors.Is(err, ErrInvalidParameter) {
+			t.Errorf("brightness %d: err = %v, want ErrInvalidParameter", b, err)
+		}
+	}
+	wantCommands(t, srv.commands())
+}
+
+func TestLightTurnOnAtStopsOnError(t *testing.T) {
+	var sent []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		sent = append(sent, r.URL.Path)
+		writeAPIError(w, 161, "device offline")
+	})
+	if err := c.LightTurnOnAt(context.Background(), "BULB01", 40); err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(sent) != 1 || !strings.HasSuffix(sent[0], "/commands") {
+		t.Errorf("sent %v, want only the failed setBrightness", sent)
+	}
+}
+
+// blockingLightServer records commands in arrival order, holding the first
+// setBrightness until release is closed.
+type blockingLightServer struct {
+	started chan struct{}
+	release chan struct{}
+
+	mu   sync.Mutex
+	sent []string
+}
+
+func (s *blockingLightServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var cmd Command
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	s.sent = append(s.sent, cmd.Command)
+	first := len(s.sent) == 1
+	s.mu.Unlock()
+	if first {
+		close(s.started)
+		<-s.release
+	}
+	success(w, r)
+}
+
+func TestLightTurnOnAtHoldsOtherCommands(t *testing.T) {
+	srv := &blockingLightServer{started: make(chan struct{}), release: make(chan struct{})}
+	c := newTestClient(t, srv.ServeHTTP)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		if err := c.LightTurnOnAt(ctx, "BULB01", 40); err != nil {
+			t.Error(err)
+		}
+	}()
+	<-srv.started
+	go func() {
+		defer wg.Done()
+		if err := c.LightTurnOff(ctx, "BULB01"); err != nil {
+			t.Error(err)
+		}
+	}()
+	// Give turnOff the chance to overtake turnOn if it isn't held back
+	time.Sleep(20 * time.Millisecond)
+	close(srv.release)
+	wg.Wait()
+
+	want := []string{"setBrightness", "turnOn", "turnOff"}
+	if !slices.Equal(srv.sent, want) {
+		t.Errorf("sent %v, want %v", srv.sent, want)
+	}
+}
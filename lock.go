@@ -0,0 +1,64 @@
+package switchbot
+
+import "context"
+
+// LockState is the bolt state of a Smart Lock.
+type LockState string
+
+const (
+	LockStateLocked   LockState = "locked"
+	LockStateUnlocked LockState = "unlocked"
+	LockStateJammed   LockState = "jammed"
+
+	// LockStateUnlatched is reported by the Lock Pro after an unlatch, which
+	// retracts the latch as well as the bolt.
+	LockStateUnlatched LockState = "unlatch"
+)
+
+// DoorState is the door sensor state of a Smart Lock.
+type DoorState string
+
+const (
+	DoorOpened DoorState = "opened"
+	DoorClosed DoorState = "closed"
+)
+
+// LockStatus is the status of a Smart Lock or Smart Lock Pro.
+//
+// Fields only some models or setups report are omitempty: DoorState needs
+// the door sensor to be installed and calibrated.
+type LockStatus struct {
+	BaseStatus
+	Version   string    `json:"version,omitempty"`
+	Battery   Battery   `json:"battery"`
+	Calibrate bool      `json:"calibrate"`
+	LockState LockState `json:"lockState"`
+	DoorState DoorState `json:"doorState,omitempty"`
+}
+
+// Jammed reports whether the lock is stuck between positions.
+func (s *LockStatus) Jammed() bool {
+	return s.LockState == LockStateJammed
+}
+
+// MarshalJSON adds the "type" field UnmarshalStatus uses to restore the
+// status.
+func (s LockStatus) MarshalJSON() ([]byte, error) {
+	type plain LockStatus
+	return marshalStatus(statusKindLock, plain(s))
+}
+
+// LockStatus fetches the status of the Smart Lock with the given id.
+func (c *Client) LockStatus(ctx context.Context, id string) (*LockStatus, error) {
+	return typedStatus[LockStatus](ctx, c, id)
+}
+
+// Lock locks the Smart Lock with the given id.
+func (c *Client) Lock(ctx context.Context, id string) error {
+	return c.SendCommand(ctx, id, Command{Command: "lock", Parameter: DefaultParameter})
+}
+
+// Unlock unlocks the Smart Lock with the given id.
+func (c *Client) Unlock(ctx context.Context, id string) error {
+	return c.SendCommand(ctx, id, Command{Command: "unlock", Parameter: DefaultParameter})
+}
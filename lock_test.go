@@ -0,0 +1,27 @@
+package switchbot
+
+import (
+	"context"
+	"testing"
+)
+
+func TestLockStatusFixtures(t *testing.T) {
+	tests := []struct {
+		fixture   string
+		jammed    bool
+		doorState DoorState
+	}{
+		{"status_lock", false, DoorClosed},
+		{"status_lock_pro", true, DoorOpened},
+	}
+	for _, tt := range tests {
+		c := newTestClient(t, serveFixture(t, tt.fixture))
+		s, err := c.LockStatus(context.Background(), "ANY")
+		if err != nil {
+			t.Fatalf("%s: %v", tt.fixture, err)
+		}
+		if s.Jammed() != tt.jammed || s.DoorState != tt.doorState {
+			t.Errorf("%s: Jammed = %v, DoorState = %q, want %v, %q", tt.fixture, s.Jammed(), s.DoorState, tt.jammed, tt.doorState)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package switchbot
+
+import "context"
+
+// MeterStatus is the status of a Meter, Meter Plus, Meter Pro, Meter Pro
+// (CO2) or Indoor/Outdoor Thermo-Hygrometer.
+//
+// Temperature is in degrees Celsius and Humidity in percent. Both accept
+// numbers or numeric strings, as firmware varies. Battery and RSSI are only
+// reported by some models and firmware, and are zero when absent. CO2 is
+// only reported by the Meter Pro (CO2) and is nil otherwise.
+type MeterStatus struct {
+	BaseStatus
+	Version     string    `json:"version"`
+	Temperature FlexFloat `json:"temperature"`
+	Humidity    FlexInt   `json:"humidity"`
+
+	// Battery is the charge in percent.
+	Battery Battery `json:"battery,omitempty"`
+	// RSSI is the signal strength seen by the hub, in dBm.
+	RSSI int `json:"rssi,omitempty"`
+	// CO2 is the carbon dioxide concentration in ppm.
+	CO2 *int `json:"CO2,omitempty"`
+}
+
+// TemperatureC returns Temperature in degrees Celsius, as reported.
+func (s *MeterStatus) TemperatureC() float64 {
+	return float64(s.Temperature)
+}
+
+// TemperatureF returns Temperature converted to degrees Fahrenheit, whatever
+// WithTemperatureUnit is set to.
+func (s *MeterStatus) TemperatureF() float64 {
+	return CelsiusToFahrenheit(float64(s.Temperature))
+}
+
+// CO2Level is a rough air quality band for a CO2 reading.
+type CO2Level string
+
+const (
+	CO2Good     CO2Level = "good"     // below 1000 ppm
+	CO2Moderate CO2Level = "moderate" // 1000 to 1499 ppm
+	CO2Poor     CO2Level = "poor"     // 1500 ppm and above
+)
+
+// CO2Level bands the CO2 reading using the thresholds of the SwitchBot app.
+// It returns "" for meters that don't report CO2.
+func (s *MeterStatus) CO2Level() CO2Level {
+	switch {
+	case s.CO2 == nil:
+		return ""
+	case *s.CO2 < 1000:
+		return CO2Good
+	case *s.CO2 < 1500:
+		return CO2Moderate
+	default:
+		return CO2Poor
+	}
+}
+
+// MarshalJSON adds the "type" field UnmarshalStatus uses to restore the
+// status.
+func (s MeterStatus) MarshalJSON() ([]byte, error) {
+	type plain MeterStatus
+	return marshalStatus(statusKindMeter, plain(s))
+}
+
+// MeterStatus fetches the status of the meter with the given id.
+func (c *Client) MeterStatus(ctx context.Context, id string) (*MeterStatus, error) {
+	return typedStatus[MeterStatus](ctx, c, id)
+}
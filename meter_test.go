@@ -0,0 +1,57 @@
+package switchbot
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCO2Level(t *testing.T) {
+	tests := []struct {
+		co2  *int
+		want CO2Level
+	}{
+		{nil, ""},
+		{intPtr(0), CO2Good},
+		{intPtr(999), CO2Good},
+		{intPtr(1000), CO2Moderate},
+		{intPtr(1499), CO2Moderate},
+		{intPtr(1500), CO2Poor},
+		{intPtr(5000), CO2Poor},
+	}
+	for _, tt := range tests {
+		s := &MeterStatus{CO2: tt.co2}
+		if got := s.CO2Level(); got != tt.want {
+			t.Errorf("CO2Level(%v) = %q, want %q", ptrValue(tt.co2), got, tt.want)
+		}
+	}
+}
+
+// ptrValue formats a possibly nil *int for test messages.
+func ptrValue(p *int) any {
+	if p == nil {
+		return "nil"
+	}
+	return *p
+}
+
+func TestMeterBatteryAndSignal(t *testing.T) {
+	tests := []struct {
+		fixture string
+		battery Battery
+		rssi    int
+	}{
+		{"status_outdoor_meter", 85, -67},
+		{"status_meter_no_battery", 0, -72},
+		{"status_meter", 95, 0},
+	}
+	for _, tt := range tests {
+		c := newTestClient(t, serveFixture(t, tt.fixture))
+		s, err := c.MeterStatus(context.Background(), "ANY")
+		if err != nil {
+			t.Fatalf("%s: %v", tt.fixture, err)
+		}
+		if s.Battery != tt.battery || s.RSSI != tt.rssi {
+			t.Errorf("%s: Battery = %d, RSSI = %d, want %d, %d", tt.fixture, s.Battery, s.RSSI, tt.battery, tt.rssi)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package switchbot
+
+import (
+	"strings"
+	"time"
+)
+
+// Metrics receives an observation for every HTTP attempt the client makes,
+// so request counts, errors and latency can be exported to Prometheus,
+// OpenTelemetry and the like.
+//
+// path is the route template, e.g. "/devices/{deviceId}/status", so ids
+// don't explode label cardinality. status is the HTTP status code, or 0 if no
+// response was received.
+type Metrics interface {
+	ObserveRequest(method, path string, status int, d time.Duration)
+}
+
+// RetryObserver may be implemented by a Metrics to count retries separately.
+// ObserveRetry is called before each retry attempt, attempt being 1 for the
+// first retry; the attempt itself is still reported through ObserveRequest.
+type RetryObserver interface {
+	ObserveRetry(method, path string, attempt int)
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) ObserveRequest(string, string, int, time.Duration) {}
+
+// metricPath replaces the id segments of an API path with placeholders.
+func metricPath(path string) string {
+	parts := strings.Split(path, "/")
+	for i := 1; i < len(parts)-1; i++ {
+		switch parts[i] {
+		case "devices":
+			parts[i+1] = "{deviceId}"
+		case "scenes":
+			parts[i+1] = "{sceneId}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,83 @@
+package switchbot
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordedRequest struct {
+	method, path string
+	status       int
+}
+
+// fakeMetrics records every observation.
+type fakeMetrics struct {
+	mu       sync.Mutex
+	requests []recordedRequest
+	retries  []int
+}
+
+func (m *fakeMetrics) ObserveRequest(method, path string, status int, _ time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests = append(m.requests, recordedRequest{method, path, status})
+}
+
+func (m *fakeMetrics) ObserveRetry(_, _ string, attempt int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retries = append(m.retries, attempt)
+}
+
+func TestMetricsObserveRetries(t *testing.T) {
+	var calls int
+	h := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		success(w, r)
+	}
+	m := &fakeMetrics{}
+	c := newTestClient(t, h, WithRetry(3, 0), WithMetrics(m))
+
+	if err := c.do(context.Background(), http.MethodGet, "/devices/ABC/status", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []recordedRequest{
+		{http.MethodGet, "/devices/{deviceId}/status", http.StatusServiceUnavailable},
+		{http.MethodGet, "/devices/{deviceId}/status", http.StatusServiceUnavailable},
+		{http.MethodGet, "/devices/{deviceId}/status", http.StatusOK},
+	}
+	if len(m.requests) != len(want) {
+		t.Fatalf("requests = %v, want %v", m.requests, want)
+	}
+	for i := range want {
+		if m.requests[i] != want[i] {
+			t.Errorf("request %d = %v, want %v", i, m.requests[i], want[i])
+		}
+	}
+	if len(m.retries) != 2 || m.retries[0] != 1 || m.retries[1] != 2 {
+		t.Errorf("retries = %v, want [1 2]", m.retries)
+	}
+}
+
+func TestMetricPath(t *testing.T) {
+	tests := map[string]string{
+		"/devices":              "/devices",
+		"/devices/ABC/status":   "/devices/{deviceId}/status",
+		"/devices/ABC/commands": "/devices/{deviceId}/commands",
+		"/scenes/S1/execute":    "/scenes/{sceneId}/execute",
+		"/webhook/queryWebhook": "/webhook/queryWebhook",
+	}
+	for path, want := range tests {
+		if got := metricPath(path); got != want {
+			t.Errorf("metricPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package switchbot
+
+import "context"
+
+// Brightness values reported by the light sensors of motion and contact
+// sensors.
+const (
+	BrightnessBright = "bright"
+	BrightnessDim    = "dim"
+)
+
+// MotionStatus is the status of a Motion Sensor.
+//
+// SwitchBot only reports whether motion is currently detected: the status
+// body carries no time of the last motion, nor how long ago it was. For "no
+// motion for 10 minutes" automations, track the TimeOfSample of MotionEvent
+// webhook events instead.
+type MotionStatus struct {
+	BaseStatus
+	Version      string  `json:"version"`
+	Battery      Battery `json:"battery"`
+	MoveDetected bool    `json:"moveDetected"`
+	// Brightness is BrightnessBright or BrightnessDim.
+	Brightness string `json:"brightness,omitempty"`
+}
+
+// MarshalJSON adds the "type" field UnmarshalStatus uses to restore the
+// status.
+func (s MotionStatus) MarshalJSON() ([]byte, error) {
+	type plain MotionStatus
+	return marshalStatus(statusKindMotion, plain(s))
+}
+
+// MotionStatus fetches the status of the Motion Sensor with the given id.
+func (c *Client) MotionStatus(ctx context.Context, id string) (*MotionStatus, error) {
+	return typedStatus[MotionStatus](ctx, c, id)
+}
+
+// Values of ContactStatus.OpenState.
+const (
+	ContactOpen            = "open"
+	ContactClosed          = "close"
+	ContactTimeOutNotClose = "timeOutNotClose"
+)
+
+// ContactStatus is the status of a Contact Sensor. As with MotionStatus,
+// there is no time of the last motion or opening; ContactEvent webhook
+// events carry it in TimeOfSample.
+type ContactStatus struct {
+	BaseStatus
+	Version      string  `json:"version"`
+	Battery      Battery `json:"battery"`
+	MoveDetected bool    `json:"moveDetected"`
+	// OpenState is ContactOpen, ContactClosed or ContactTimeOutNotClose,
+	// the latter once the door has been left open for the time set in the
+	// app.
+	OpenState  string `json:"openState"`
+	Brightness string `json:"brightness,omitempty"`
+}
+
+// Open reports whether the door or window is open, including when it has
+// been left open past the timeout.
+func (s *ContactStatus) Open() bool {
+	return s.OpenState == ContactOpen || s.OpenState == ContactTimeOutNotClose
+}
+
+// MarshalJSON adds the "type" field UnmarshalStatus uses to restore the
+// status.
+func (s ContactStatus) MarshalJSON() ([]byte, error) {
+	type plain ContactStatus
+	return marshalStatus(statusKindContact, plain(s))
+}
+
+// ContactStatus fetches the status of the Contact Sensor with the given id.
+func (c *Client) ContactStatus(ctx context.Context, id string) (*ContactStatus, error) {
+	return typedStatus[ContactStatus](ctx, c, id)
+}
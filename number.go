@@ -0,0 +1,111 @@
+package switchbot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// FlexFloat is a float64 that decodes from a JSON number or a numeric
+// string, since some firmware quotes readings like "23.5". It always encodes
+// as a number.
+type FlexFloat float64
+
+func (f *FlexFloat) UnmarshalJSON(b []byte) error {
+	v, err := parseFlexNumber(b)
+	if err != nil {
+		return err
+	}
+	*f = FlexFloat(v)
+	return nil
+}
+
+// FlexInt is an int that decodes from a JSON number or a numeric string.
+// Whole-valued floats such as 45.0 are accepted; fractional values are an
+// error. It always encodes as a number.
+type FlexInt int
+
+func (n *FlexInt) UnmarshalJSON(b []byte) error {
+	v, err := parseFlexInt(b)
+	if err != nil {
+		return err
+	}
+	*n = FlexInt(v)
+	return nil
+}
+
+// UnixMillis is a Unix timestamp in milliseconds, as used by webhook events
+// and webhook details. It decodes like FlexInt, and integers are parsed
+// exactly rather than through float64, which can't represent every value
+// above 2^53. It always encodes as a number.
+type UnixMillis int64
+
+func (m *UnixMillis) UnmarshalJSON(b []byte) error {
+	v, err := parseFlexInt(b)
+	if err != nil {
+		return err
+	}
+	*m = UnixMillis(v)
+	return nil
+}
+
+// Time returns the timestamp as a time.Time, or the zero Time for 0.
+func (m UnixMillis) Time() time.Time {
+	if m == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(int64(m))
+}
+
+// parseFlexInt parses a JSON integer, numeric string or null (as 0).
+// Integers without a fraction or exponent are parsed exactly; others go
+// through parseFlexNumber and must be whole.
+func parseFlexInt(b []byte) (int64, error) {
+	s := string(bytes.TrimSpace(b))
+	if len(s) > 1 && s[0] == '"' {
+		if unquoted, err := strconv.Unquote(s); err == nil {
+			s = unquoted
+		}
+	}
+	if v, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return v, nil
+	}
+
+	v, err := parseFlexNumber(b)
+	if err != nil {
+		return 0, err
+	}
+	if v != math.Trunc(v) || v < math.MinInt64 || v >= math.MaxInt64 {
+		return 0, fmt.Errorf("invalid integer %s", b)
+	}
+	return int64(v), nil
+}
+
+// parseFlexNumber parses a JSON number, numeric string or null (as 0).
+func parseFlexNumber(b []byte) (float64, error) {
+	b = bytes.TrimSpace(b)
+	if bytes.Equal(b, []byte("null")) {
+		return 0, nil
+	}
+
+	if len(b) > 0 && b[0] == '"' {
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return 0, err
+		}
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number %q", s)
+		}
+		return v, nil
+	}
+
+	var v float64
+	if err := json.Unmarshal(b, &v); err != nil {
+		return 0, fmt.Errorf("invalid number %s", b)
+	}
+	return v, nil
+}
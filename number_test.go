@@ -0,0 +1,81 @@
+package switchbot
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestUnixMillisAbove2To53(t *testing.T) {
+	// 2^53 + 1 is the smallest integer float64 can't represent.
+	const ts = 9007199254740993
+
+	var event WebhookEvent
+	body := `{"eventType":"changeReport","eventVersion":"1","context":{"deviceType":"WoContact","deviceMac":"CONTACT01","timeOfSample":9007199254740993}}`
+	if err := json.Unmarshal([]byte(body), &event); err != nil {
+		t.Fatal(err)
+	}
+	if got := int64(event.Context.TimeOfSample); got != ts {
+		t.Errorf("timeOfSample = %d, want %d", got, int64(ts))
+	}
+
+	for _, raw := range []string{`9007199254740993`, `"9007199254740993"`} {
+		var w Webhook
+		if err := json.Unmarshal([]byte(`{"url":"https://example.com/hook","createTime":`+raw+`}`), &w); err != nil {
+			t.Fatalf("createTime %s: %v", raw, err)
+		}
+		if got := int64(w.CreateTime); got != ts {
+			t.Errorf("createTime %s = %d, want %d", raw, got, int64(ts))
+		}
+	}
+}
+
+func TestFlexNumbers(t *testing.T) {
+	tests := []struct {
+		raw       string
+		wantFloat FlexFloat
+		wantInt   FlexInt
+		intErr    bool
+	}{
+		{raw: `22.5`, wantFloat: 22.5, intErr: true},
+		{raw: `"22.5"`, wantFloat: 22.5, intErr: true},
+		{raw: `52`, wantFloat: 52, wantInt: 52},
+		{raw: `"52"`, wantFloat: 52, wantInt: 52},
+		{raw: `45.0`, wantFloat: 45, wantInt: 45},
+		{raw: `"-3"`, wantFloat: -3, wantInt: -3},
+		{raw: `null`},
+	}
+	for _, tt := range tests {
+		var f FlexFloat
+		if err := json.Unmarshal([]byte(tt.raw), &f); err != nil || f != tt.wantFloat {
+			t.Errorf("FlexFloat %s = %v, %v, want %v", tt.raw, f, err, tt.wantFloat)
+		}
+		var n FlexInt
+		err := json.Unmarshal([]byte(tt.raw), &n)
+		if tt.intErr {
+			if err == nil {
+				t.Errorf("FlexInt %s = %v, want an error for a fraction", tt.raw, n)
+			}
+		} else if err != nil || n != tt.wantInt {
+			t.Errorf("FlexInt %s = %v, %v, want %v", tt.raw, n, err, tt.wantInt)
+		}
+	}
+
+	for _, raw := range []string{`"warm"`, `""`, `"52%"`, `true`, `{}`} {
+		var f FlexFloat
+		if err := json.Unmarshal([]byte(raw), &f); err == nil {
+			t.Errorf("FlexFloat %s = %v, want an error", raw, f)
+		}
+		var n FlexInt
+		if err := json.Unmarshal([]byte(raw), &n); err == nil {
+			t.Errorf("FlexInt %s = %v, want an error", raw, n)
+		}
+	}
+}
+
+func TestMeterStatusInvalidString(t *testing.T) {
+	var s MeterStatus
+	body := `{"deviceId":"METER01","deviceType":"Meter","temperature":"warm","humidity":"52"}`
+	if err := json.Unmarshal([]byte(body), &s); err == nil {
+		t.Errorf("decoded %+v, want an error for a non-numeric temperature", s)
+	}
+}
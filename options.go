@@ -0,0 +1,315 @@
+package switchbot
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithBaseURL overrides the API host, e.g. to point the client at a test
+// server. It takes precedence over WithRegion.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithHTTPClient replaces the HTTP client used for requests. The client's own
+// redirect policy is kept; see StripCredentialsOnRedirect.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// WithTimeout sets the timeout of the HTTP client the library creates,
+// DefaultTimeout unless set; 0 means no timeout. It is ignored when
+// WithHTTPClient is used, whatever the option order, since a caller's
+// client is never modified.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.httpTimeout = d
+	}
+}
+
+// WithRequestTimeout bounds each API call, including any time spent waiting
+// on the rate limiter, to d. It only applies when the caller's context has no
+// deadline of its own; a caller-supplied deadline is never shortened or
+// extended.
+func WithRequestTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.requestTimeout = d
+	}
+}
+
+// WithRateLimit limits the client to perSecond requests per second on
+// average, allowing bursts of up to burst requests. SwitchBot allows 10,000
+// requests per day per token.
+func WithRateLimit(perSecond float64, burst int) Option {
+	return func(c *Client) {
+		c.limiter = newRateLimiter(perSecond, burst)
+	}
+}
+
+// WithConcurrency sets how many requests batch helpers such as SnapshotAll
+// run at once. Values below 1 are ignored.
+func WithConcurrency(n int) Option {
+	return func(c *Client) {
+		if n > 0 {
+			c.concurrency = n
+		}
+	}
+}
+
+// WithRetry retries requests that fail with a transport error, a 429 or a 5xx
+// response up to maxRetries times, backing off exponentially from baseDelay
+// up to 30 seconds; a zero baseDelay retries immediately. Envelope errors
+// such as an offline device are never retried.
+func WithRetry(maxRetries int, baseDelay time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.retryBaseDelay = baseDelay
+	}
+}
+
+// WithMetrics reports every HTTP attempt, including retries, to m.
+func WithMetrics(m Metrics) Option {
+	return func(c *Client) {
+		if m == nil {
+			m = noopMetrics{}
+		}
+		c.metrics = m
+	}
+}
+
+// WithStatusETags makes status reads conditional: the ETag of each device's
+// last status is sent back as If-None-Match and a 304 answer is served from
+// the remembered status. SwitchBot doesn't document ETag support, so this is
+// off by default; without ETags in the responses it behaves like a normal
+// fetch.
+func WithStatusETags() Option {
+	return func(c *Client) {
+		c.etags = newETagCache()
+	}
+}
+
+// WithPollInterval sets the time between status reads of polling helpers
+// such as WaitForState. Every read counts against the daily request quota.
+func WithPollInterval(d time.Duration) Option {
+	return func(c *Client) {
+		if d > 0 {
+			c.pollInterval = d
+		}
+	}
+}
+
+// WithStrictDecoding makes decoding into typed structs fail when the API
+// returns a field the struct doesn't model. It is meant for CI runs against
+// recorded responses, to notice API drift; the default lenient decoding
+// keeps production code working when SwitchBot adds fields.
+func WithStrictDecoding(strict bool) Option {
+	return func(c *Client) {
+		c.strict = strict
+	}
+}
+
+// WithDeviceCache caches the device list, and the DeviceIndex built from it,
+// for ttl. Helpers that need the device list, such as ResolveID, then cost
+// one request per ttl instead of one per call. The scene list used by
+// ExecuteSceneByName is cached for ttl as well. SaveCache and LoadCache
+// carry the caches across short-lived processes.
+func WithDeviceCache(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.devices = &deviceCache{ttl: ttl}
+		c.scenes = &sceneCache{ttl: ttl}
+	}
+}
+
+// WithCommandCooldown enforces a minimum interval between commands sent to
+// the same device, to stay clear of rate limits and spare the hardware.
+// policy decides whether a command sent too soon waits or fails with
+// ErrCooldown. Status reads are not affected.
+func WithCommandCooldown(interval time.Duration, policy CooldownPolicy) Option {
+	return func(c *Client) {
+		c.cooldown = newCooldown(interval, policy)
+	}
+}
+
+// WithMaxResponseBytes caps how much of a response body is read, failing the
+// request with ErrResponseTooLarge beyond n bytes. It guards against a broken
+// upstream exhausting memory. The default is DefaultMaxResponseBytes.
+func WithMaxResponseBytes(n int64) Option {
+	return func(c *Client) {
+		if n > 0 {
+			c.maxBodyBytes = n
+		}
+	}
+}
+
+// WithLogger logs every HTTP attempt to l at debug level, including the
+// nonce and any correlation id set with WithCorrelationID.
+func WithLogger(l *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// WithCompression asks for gzip responses and decompresses them, passing
+// uncompressed responses through unchanged. Go's default transport already
+// negotiates gzip transparently; this is for transports that don't, such as
+// one with DisableCompression set. It is off by default.
+func WithCompression(enabled bool) Option {
+	return func(c *Client) {
+		c.compression = enabled
+	}
+}
+
+// WithClockSkewRetry enables a single retry of a request that fails with
+// HTTP 401, re-signed with a timestamp corrected by the response's Date
+// header. SwitchBot rejects signatures whose timestamp is too far from its
+// own clock, so this recovers from a skewed local clock. The retry only
+// happens when the Date header shows a skew of at least two seconds, and the
+// learned offset is kept for later requests.
+func WithClockSkewRetry(enabled bool) Option {
+	return func(c *Client) {
+		c.skewRetry = enabled
+	}
+}
+
+// WithTransportConfig tunes connection reuse for clients polling the API
+// heavily: maxIdleConns and maxIdleConnsPerHost bound the idle keep-alive
+// connections kept open, and idleTimeout closes them after that long unused.
+// Since every request goes to one host, maxIdleConnsPerHost is the setting
+// that matters; Go's default of 2 causes connection churn above a couple of
+// concurrent requests.
+//
+// The settings are applied to a copy of http.DefaultTransport used by the
+// client's default HTTP client. They are ignored if WithHTTPClient is also
+// given, whatever the order: configure that client's transport directly.
+func WithTransportConfig(maxIdleConns, maxIdleConnsPerHost int, idleTimeout time.Duration) Option {
+	return func(c *Client) {
+		c.transport = &transportConfig{
+			maxIdleConns:        maxIdleConns,
+			maxIdleConnsPerHost: maxIdleConnsPerHost,
+			idleTimeout:         idleTimeout,
+		}
+	}
+}
+
+// WithNonceGenerator replaces the generator of request nonces, which by
+// default are random UUIDs from crypto/rand. A nil g is ignored.
+func WithNonceGenerator(g NonceGenerator) Option {
+	return func(c *Client) {
+		if g != nil {
+			c.nonces = g
+		}
+	}
+}
+
+// WithCommandTimeouts bounds commands by the timeout for the target device's
+// type when the caller's context has no deadline, taking precedence over
+// WithRequestTimeout. A nil map uses DefaultCommandTimeouts. Finding a
+// device's type needs the device list, so combine this with WithDeviceCache
+// to avoid a list fetch per command.
+func WithCommandTimeouts(timeouts map[DeviceType]time.Duration) Option {
+	return func(c *Client) {
+		c.commandTimeouts = cloneTimeouts(timeouts)
+	}
+}
+
+// WithTemperatureUnit sets the unit of temperatures passed to the client,
+// such as ACSettings.Temperature when ACSettings.Unit is empty. The default
+// is Celsius.
+func WithTemperatureUnit(u TemperatureUnit) Option {
+	return func(c *Client) {
+		c.temperatureUnit = u
+	}
+}
+
+// WithCircuitBreaker stops the client hammering the API during an outage:
+// after failures consecutive failed attempts (transport errors, timeouts,
+// 429s and 5xx responses), requests fail fast with ErrCircuitOpen until
+// cooldown has passed. Then a single request is let through; if it succeeds
+// the breaker closes, otherwise it stays open for another cooldown. Retries
+// count as attempts; requests the caller cancels don't count either way.
+func WithCircuitBreaker(failures int, cooldown time.Duration) Option {
+	return func(c *Client) {
+		c.breaker = newBreaker(failures, cooldown)
+	}
+}
+
+// WithTrafficRecorder writes every HTTP attempt, with its request and
+// response headers and bodies, to w as one JSON TrafficEntry per line, for
+// debugging problems that can't be reproduced locally or for capturing
+// fixtures. The Authorization and sign headers are redacted and the token
+// and secret are scrubbed from everything else. Writes are serialised, and
+// write errors are ignored. See WithTrafficIndent for indented entries.
+func WithTrafficRecorder(w io.Writer) Option {
+	return func(c *Client) {
+		c.recorder = newTrafficRecorder(w)
+	}
+}
+
+// WithRegion selects the API host for a SwitchBot region. Only RegionGlobal,
+// the default, exists today. NewClient returns an error wrapping
+// ErrInvalidParameter for an unknown region. A base URL given with
+// WithBaseURL is used instead, whichever option comes first.
+func WithRegion(region string) Option {
+	return func(c *Client) {
+		c.region = strings.ToLower(strings.TrimSpace(region))
+	}
+}
+
+// WithBackoffJitter sets how retry delays are randomised: FullJitter (the
+// default), EqualJitter, NoJitter or a custom Jitter.
+func WithBackoffJitter(j Jitter) Option {
+	return func(c *Client) {
+		c.jitter = j
+	}
+}
+
+// WithCommandResults remembers the outcome of the last command sent to each
+// device, for LastCommandResult. At most capacity devices are remembered,
+// the least recently commanded being forgotten first; 0 means
+// DefaultCommandResultCapacity.
+func WithCommandResults(capacity int) Option {
+	return func(c *Client) {
+		c.results = newResultStore(capacity)
+	}
+}
+
+// WithHubCheck makes SendCommand check the device list before sending to a
+// Bluetooth device, such as a Bot or Lock, and fail with an error wrapping
+// ErrNoHub if the device isn't paired with a hub, rather than have the API
+// answer with statusCode 171. The list is fetched once per command unless
+// WithDeviceCache is set.
+func WithHubCheck(enabled bool) Option {
+	return func(c *Client) {
+		c.hubCheck = enabled
+	}
+}
+
+// WithSceneHistory logs every scene execution for SceneHistory, keeping the
+// last capacity entries; 0 means DefaultSceneHistoryCapacity.
+func WithSceneHistory(capacity int) Option {
+	return func(c *Client) {
+		c.sceneHistory = newSceneHistory(capacity)
+	}
+}
+
+// WithTrafficIndent makes WithTrafficRecorder write each entry indented by
+// indent per level, request and response bodies included, for reading
+// recordings by eye. Entries then span several lines but still decode one
+// after another with a json.Decoder. It only changes the recording: bodies
+// are always sent to SwitchBot compact. The default, "", writes one compact
+// entry per line.
+func WithTrafficIndent(indent string) Option {
+	return func(c *Client) {
+		c.trafficIndent = indent
+	}
+}
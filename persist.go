@@ -0,0 +1,130 @@
+package switchbot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrNoDeviceCache is returned by SaveCache and LoadCache on a client
+// created without WithDeviceCache.
+var ErrNoDeviceCache = errors.New("device cache not enabled")
+
+// cacheFileVersion is bumped when the cache file format changes; files with
+// another version are ignored.
+const cacheFileVersion = 1
+
+// cacheFile is the on-disk form of the device and scene caches. Each list
+// keeps the time it was fetched, so the TTL runs from the original fetch
+// rather than from the load.
+type cacheFile struct {
+	Version        int         `json:"version"`
+	Devices        *deviceList `json:"devices,omitempty"`
+	DevicesFetched time.Time   `json:"devicesFetched,omitempty"`
+	Scenes         []Scene     `json:"scenes,omitempty"`
+	ScenesFetched  time.Time   `json:"scenesFetched,omitempty"`
+
+	// Annotations are the SetAnnotation values by device id. They don't
+	// expire.
+	Annotations map[string]map[string]string `json:"annotations,omitempty"`
+}
+
+// SaveCache writes the cached device list and scene list to path, along with
+// the device annotations, for a later process to restore with LoadCache. It
+// is meant for CLI tools run repeatedly, which would otherwise fetch the
+// device list on every run. Nothing is written if there is nothing to save,
+// unless annotations were set, removed or loaded: the file is then rewritten
+// so a removed annotation doesn't come back from an older file.
+// The file is replaced atomically and is readable only by the owner,
+// although it holds no credentials.
+func (c *Client) SaveCache(path string) error {
+	if c.devices == nil {
+		return ErrNoDeviceCache
+	}
+
+	f := cacheFile{Version: cacheFileVersion}
+	c.devices.mu.Lock()
+	f.Devices, f.DevicesFetched = c.devices.list, c.devices.fetched
+	c.devices.mu.Unlock()
+	c.scenes.mu.Lock()
+	f.Scenes, f.ScenesFetched = c.scenes.scenes, c.scenes.fetched
+	c.scenes.mu.Unlock()
+	f.Annotations = c.annotations.all()
+	if f.Devices == nil && f.Scenes == nil && f.Annotations == nil && !c.annotations.changed() {
+		return nil
+	}
+
+	data, err := json.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("error encoding cache: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("error saving cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error saving cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error saving cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("error saving cache: %w", err)
+	}
+	return nil
+}
+
+// LoadCache restores the device list, scene list and annotations saved by
+// SaveCache. Lists older than the WithDeviceCache TTL are discarded, as are
+// lists older than what the client already holds; annotations are always
+// restored, except for keys already set on the client. A missing,
+// unreadable or corrupt file is ignored, logged as a warning when WithLogger
+// is set, and the lists are fetched as usual when next needed; only a client
+// without WithDeviceCache gets an error.
+func (c *Client) LoadCache(path string) error {
+	if c.devices == nil {
+		return ErrNoDeviceCache
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	var f cacheFile
+	if err == nil {
+		err = json.Unmarshal(data, &f)
+	}
+	if err == nil && f.Version != cacheFileVersion {
+		err = fmt.Errorf("unsupported cache version %d", f.Version)
+	}
+	if err != nil {
+		if c.logger != nil {
+			c.logger.Warn("switchbot ignoring device cache file", "path", path, "error", err)
+		}
+		return nil
+	}
+
+	c.devices.mu.Lock()
+	if f.Devices != nil && time.Since(f.DevicesFetched) < c.devices.ttl && f.DevicesFetched.After(c.devices.fetched) {
+		c.devices.list = f.Devices
+		c.devices.index = newDeviceIndex(f.Devices)
+		c.devices.fetched = f.DevicesFetched
+	}
+	c.devices.mu.Unlock()
+
+	c.scenes.mu.Lock()
+	if f.Scenes != nil && time.Since(f.ScenesFetched) < c.scenes.ttl && f.ScenesFetched.After(c.scenes.fetched) {
+		c.scenes.scenes = f.Scenes
+		c.scenes.fetched = f.ScenesFetched
+	}
+	c.scenes.mu.Unlock()
+
+	c.annotations.merge(f.Annotations)
+	return nil
+}
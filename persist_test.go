@@ -0,0 +1,175 @@
+package switchbot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// countingServer serves a device list and a scene list, counting the list
+// fetches.
+func countingServer(devices *int, scenes *int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case apiVersion + "/devices":
+			*devices++
+			writeEnvelope(w, statusSuccess, deviceList{DeviceList: []Device{{DeviceID: "BOT01", DeviceName: "Kettle", DeviceType: DeviceTypeBot}}})
+		case apiVersion + "/scenes":
+			*scenes++
+			writeEnvelope(w, statusSuccess, []Scene{{SceneID: "SCENE01", SceneName: "Good Night"}})
+		default:
+			success(w, r)
+		}
+	}
+}
+
+func TestCacheRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	ctx := context.Background()
+
+	var devices, scenes int
+	first := newTestClient(t, countingServer(&devices, &scenes), WithDeviceCache(time.Hour))
+	if _, err := first.Devices(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := first.ExecuteSceneByName(ctx, "Good Night"); err != nil {
+		t.Fatal(err)
+	}
+	first.SetAnnotation("BOT01", "room", "kitchen")
+	if err := first.SaveCache(path); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("cache file mode = %v, %v, want 0600", info.Mode().Perm(), err)
+	}
+
+	devices, scenes = 0, 0
+	second := newTestClient(t, countingServer(&devices, &scenes), WithDeviceCache(time.Hour))
+	if err := second.LoadCache(path); err != nil {
+		t.Fatal(err)
+	}
+	list, err := second.Devices(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := second.ExecuteSceneByName(ctx, "Good Night"); err != nil {
+		t.Fatal(err)
+	}
+	if devices != 0 || scenes != 0 {
+		t.Errorf("fetched the device list %d and scene list %d times after loading the cache, want 0", devices, scenes)
+	}
+	if len(list) != 1 || list[0].DeviceName != "Kettle" {
+		t.Errorf("devices = %+v, want the cached list", list)
+	}
+	if got := second.Annotations("BOT01")["room"]; got != "kitchen" {
+		t.Errorf("annotation room = %q, want kitchen", got)
+	}
+}
+
+func TestLoadCacheExpired(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	data, _ := json.Marshal(cacheFile{
+		Version:        cacheFileVersion,
+		Devices:        &deviceList{DeviceList: []Device{{DeviceID: "OLD01"}}},
+		DevicesFetched: time.Now().Add(-2 * time.Hour),
+	})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var devices, scenes int
+	c := newTestClient(t, countingServer(&devices, &scenes), WithDeviceCache(time.Hour))
+	if err := c.LoadCache(path); err != nil {
+		t.Fatal(err)
+	}
+	list, err := c.Devices(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if devices != 1 || list[0].DeviceID != "BOT01" {
+		t.Errorf("devices = %+v after %d fetches, want the expired list refetched", list, devices)
+	}
+}
+
+func TestLoadCacheCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"corrupt.json": "{not json",
+		"version.json": `{"version":99,"devices":{"deviceList":[{"deviceId":"X"}]}}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		var logs bytes.Buffer
+		var devices, scenes int
+		c := newTestClient(t, countingServer(&devices, &scenes), WithDeviceCache(time.Hour), WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+		if err := c.LoadCache(path); err != nil {
+			t.Errorf("%s: %v, want the file ignored", name, err)
+		}
+		if _, err := c.Devices(context.Background()); err != nil || devices != 1 {
+			t.Errorf("%s: %d fetches, %v, want the list refetched", name, devices, err)
+		}
+		if !strings.Contains(logs.String(), "ignoring device cache file") {
+			t.Errorf("%s: no warning logged", name)
+		}
+	}
+
+	var devices, scenes int
+	c := newTestClient(t, countingServer(&devices, &scenes), WithDeviceCache(time.Hour))
+	if err := c.LoadCache(filepath.Join(dir, "missing.json")); err != nil {
+		t.Errorf("missing file: %v", err)
+	}
+}
+
+func TestCacheNeedsDeviceCache(t *testing.T) {
+	c, _ := newCommandClient(t)
+	path := filepath.Join(t.TempDir(), "cache.json")
+	if err := c.SaveCache(path); !errors.Is(err, ErrNoDeviceCache) {
+		t.Errorf("SaveCache: err = %v, want ErrNoDeviceCache", err)
+	}
+	if err := c.LoadCache(path); !errors.Is(err, ErrNoDeviceCache) {
+		t.Errorf("LoadCache: err = %v, want ErrNoDeviceCache", err)
+	}
+}
+
+func TestSaveCacheRemovedLastAnnotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	var devices, scenes int
+
+	first := newTestClient(t, countingServer(&devices, &scenes), WithDeviceCache(time.Hour))
+	first.SetAnnotation("BOT01", "room", "kitchen")
+	if err := first.SaveCache(path); err != nil {
+		t.Fatal(err)
+	}
+
+	second := newTestClient(t, countingServer(&devices, &scenes), WithDeviceCache(time.Hour))
+	if err := second.LoadCache(path); err != nil {
+		t.Fatal(err)
+	}
+	second.SetAnnotation("BOT01", "room", "")
+	if err := second.SaveCache(path); err != nil {
+		t.Fatal(err)
+	}
+
+	third := newTestClient(t, countingServer(&devices, &scenes), WithDeviceCache(time.Hour))
+	if err := third.LoadCache(path); err != nil {
+		t.Fatal(err)
+	}
+	if got := third.Annotations("BOT01"); got != nil {
+		t.Errorf("removed annotation came back: %v", got)
+	}
+	if devices != 0 || scenes != 0 {
+		t.Errorf("fetched the device list %d and scene list %d times, want 0", devices, scenes)
+	}
+}
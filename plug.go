@@ -0,0 +1,97 @@
+package switchbot
+
+import (
+	"context"
+	"time"
+)
+
+// electricCurrentScale converts the Plug Mini's electricCurrent to amps.
+// The API reference documents the field in amps, but Plug Mini (US) and
+// (JP) firmware report it in milliamps: a 25 W load at 120 V reads ~208.
+const electricCurrentScale = 0.001
+
+// PlugStatus is the status of a Plug or Plug Mini.
+//
+// Voltage, Weight and ElectricCurrent hold the numbers exactly as SwitchBot
+// reports them; use Volts, Watts and Amps for values in SI units. Only the
+// Plug Mini reports power metrics, so they are zero for the original Plug.
+type PlugStatus struct {
+	BaseStatus
+	Version string     `json:"version"`
+	Power   PowerState `json:"power"`
+
+	// Voltage is the mains voltage in volts.
+	Voltage float64 `json:"voltage"`
+	// Weight is the instantaneous power draw in watts, despite its name.
+	Weight float64 `json:"weight"`
+	// ElectricityOfDay is how many minutes the plug has been on today.
+	ElectricityOfDay int `json:"electricityOfDay"`
+	// ElectricCurrent is the current draw in milliamps.
+	ElectricCurrent float64 `json:"electricCurrent"`
+}
+
+// Volts returns the mains voltage in volts.
+func (s *PlugStatus) Volts() float64 {
+	return s.Voltage
+}
+
+// Watts returns the power draw in watts.
+func (s *PlugStatus) Watts() float64 {
+	return s.Weight
+}
+
+// Amps returns the current draw in amps.
+func (s *PlugStatus) Amps() float64 {
+	return s.ElectricCurrent * electricCurrentScale
+}
+
+// MarshalJSON adds the "type" field UnmarshalStatus uses to restore the
+// status.
+func (s PlugStatus) MarshalJSON() ([]byte, error) {
+	type plain PlugStatus
+	return marshalStatus(statusKindPlug, plain(s))
+}
+
+// PlugStatus fetches the status of the Plug or Plug Mini with the given id.
+func (c *Client) PlugStatus(ctx context.Context, id string) (*PlugStatus, error) {
+	return typedStatus[PlugStatus](ctx, c, id)
+}
+
+// UsageHistory is the electricity usage history of a plug.
+//
+// SwitchBot exposes no history endpoint: the only aggregate is the Plug
+// Mini's electricityOfDay, so Days holds a single entry for today, and is
+// empty for the original Plug, which reports no usage. The slice leaves room
+// for richer history should the API add it.
+type UsageHistory struct {
+	DeviceID string
+	Days     []DailyUsage
+}
+
+// DailyUsage is a plug's usage over one day.
+type DailyUsage struct {
+	// Date is local midnight at the start of the day.
+	Date time.Time
+	// OnDuration is how long the plug was on, at minute resolution.
+	OnDuration time.Duration
+}
+
+// PlugUsageHistory returns the usage history of the Plug Mini with the given
+// id. See UsageHistory for its limits.
+func (c *Client) PlugUsageHistory(ctx context.Context, id string) (*UsageHistory, error) {
+	status, err := c.PlugStatus(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &UsageHistory{DeviceID: id}
+	if status.DeviceType == DeviceTypePlug {
+		return history, nil
+	}
+	y, m, d := time.Now().Date()
+	history.Days = []DailyUsage{{
+		Date:       time.Date(y, m, d, 0, 0, 0, 0, time.Local),
+		OnDuration: time.Duration(status.ElectricityOfDay) * time.Minute,
+	}}
+	return history, nil
+}
@@ -0,0 +1,57 @@
+package switchbot
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestPlugUnits(t *testing.T) {
+	c := newTestClient(t, serveFixture(t, "status_plug"))
+
+	s, err := c.PlugStatus(context.Background(), "DEV000000003")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.ElectricCurrent != 350 || math.Abs(s.Amps()-0.35) > 1e-9 {
+		t.Errorf("electricCurrent %v = %v A, want 350 mA = 0.35 A", s.ElectricCurrent, s.Amps())
+	}
+	if s.Volts() != 120.3 {
+		t.Errorf("Volts = %v, want 120.3", s.Volts())
+	}
+	if s.Watts() != 42 {
+		t.Errorf("Watts = %v, want 42", s.Watts())
+	}
+}
+
+func TestPlugUsageHistory(t *testing.T) {
+	c := newTestClient(t, serveFixture(t, "status_plug"))
+
+	h, err := c.PlugUsageHistory(context.Background(), "DEV000000003")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.DeviceID != "DEV000000003" || len(h.Days) != 1 {
+		t.Fatalf("history = %+v, want one day for DEV000000003", h)
+	}
+	y, m, d := time.Now().Date()
+	if day := h.Days[0]; !day.Date.Equal(time.Date(y, m, d, 0, 0, 0, 0, time.Local)) || day.OnDuration != 95*time.Minute {
+		t.Errorf("day = %+v, want today with 95 minutes on", day)
+	}
+}
+
+func TestPlugUsageHistoryEmpty(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeEnvelope(w, statusSuccess, map[string]any{"deviceId": "PLUG01", "deviceType": "Plug", "power": "on", "version": "V1.0"})
+	})
+
+	h, err := c.PlugUsageHistory(context.Background(), "PLUG01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.DeviceID != "PLUG01" || len(h.Days) != 0 {
+		t.Errorf("history = %+v, want no days for the original Plug", h)
+	}
+}
@@ -0,0 +1,190 @@
+// Package prometheus exports the switchbot client's request metrics in the
+// Prometheus text exposition format, without depending on the Prometheus
+// client library.
+//
+//	m := prometheus.New()
+//	client, err := switchbot.NewClient(token, secret, switchbot.WithMetrics(m))
+//	...
+//	http.Handle("/metrics", m)
+package prometheus
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets are the latency histogram bucket upper bounds, in seconds.
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Metrics collects the client's observations and serves them as an
+// http.Handler. It implements switchbot.Metrics and switchbot.RetryObserver.
+// The zero value is not usable; call New.
+type Metrics struct {
+	buckets []float64
+
+	mu        sync.Mutex
+	requests  map[requestKey]uint64
+	errors    map[requestKey]uint64
+	retries   map[routeKey]uint64
+	latencies map[routeKey]*histogram
+}
+
+// routeKey identifies an API route, path being the route template.
+type routeKey struct {
+	method, path string
+}
+
+type requestKey struct {
+	routeKey
+	code int
+}
+
+type histogram struct {
+	counts []uint64 // per bucket, not cumulative
+	sum    float64
+	count  uint64
+}
+
+// New returns an empty Metrics. buckets overrides DefaultBuckets; they are
+// sorted and need not include +Inf.
+func New(buckets ...float64) *Metrics {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = slices.Clone(buckets)
+	slices.Sort(buckets)
+	return &Metrics{
+		buckets:   buckets,
+		requests:  make(map[requestKey]uint64),
+		errors:    make(map[requestKey]uint64),
+		retries:   make(map[routeKey]uint64),
+		latencies: make(map[routeKey]*histogram),
+	}
+}
+
+// ObserveRequest records one HTTP attempt. Any status other than 200,
+// including 0 for no response, counts as an error.
+func (m *Metrics) ObserveRequest(method, path string, status int, d time.Duration) {
+	route := routeKey{method, path}
+	key := requestKey{route, status}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests[key]++
+	if status != http.StatusOK {
+		m.errors[key]++
+	}
+
+	h, ok := m.latencies[route]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(m.buckets))}
+		m.latencies[route] = h
+	}
+	s := d.Seconds()
+	if i, _ := slices.BinarySearch(m.buckets, s); i < len(m.buckets) {
+		h.counts[i]++
+	}
+	h.sum += s
+	h.count++
+}
+
+// ObserveRetry records a retry of a request.
+func (m *Metrics) ObserveRetry(method, path string, attempt int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retries[routeKey{method, path}]++
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WriteTo(w)
+}
+
+// WriteTo writes the metrics in the Prometheus text format to w.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+
+	m.mu.Lock()
+	writeCounters(&b, "switchbot_requests_total", "HTTP requests sent to the SwitchBot API.", m.requests)
+	writeCounters(&b, "switchbot_request_errors_total", "HTTP requests that did not return 200, by status code (0 for no response).", m.errors)
+
+	b.WriteString("# HELP switchbot_retries_total Requests retried after a failure.\n")
+	b.WriteString("# TYPE switchbot_retries_total counter\n")
+	for _, route := range sortedRoutes(m.retries) {
+		fmt.Fprintf(&b, "switchbot_retries_total{%s} %d\n", route.labels(), m.retries[route])
+	}
+
+	b.WriteString("# HELP switchbot_request_duration_seconds Latency of HTTP requests to the SwitchBot API.\n")
+	b.WriteString("# TYPE switchbot_request_duration_seconds histogram\n")
+	for _, route := range sortedRoutes(m.latencies) {
+		h := m.latencies[route]
+		var cumulative uint64
+		for i, le := range m.buckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(&b, "switchbot_request_duration_seconds_bucket{%s,le=%q} %d\n", route.labels(), formatFloat(le), cumulative)
+		}
+		fmt.Fprintf(&b, "switchbot_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", route.labels(), h.count)
+		fmt.Fprintf(&b, "switchbot_request_duration_seconds_sum{%s} %s\n", route.labels(), formatFloat(h.sum))
+		fmt.Fprintf(&b, "switchbot_request_duration_seconds_count{%s} %d\n", route.labels(), h.count)
+	}
+	m.mu.Unlock()
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func writeCounters(b *strings.Builder, name, help string, counts map[requestKey]uint64) {
+	fmt.Fprintf(b, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(b, "# TYPE %s counter\n", name)
+
+	keys := make([]requestKey, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b requestKey) int {
+		if c := a.compare(b.routeKey); c != 0 {
+			return c
+		}
+		return a.code - b.code
+	})
+	for _, k := range keys {
+		fmt.Fprintf(b, "%s{%s,code=\"%d\"} %d\n", name, k.labels(), k.code, counts[k])
+	}
+}
+
+func sortedRoutes[V any](m map[routeKey]V) []routeKey {
+	routes := make([]routeKey, 0, len(m))
+	for r := range m {
+		routes = append(routes, r)
+	}
+	slices.SortFunc(routes, routeKey.compare)
+	return routes
+}
+
+func (r routeKey) compare(o routeKey) int {
+	if c := strings.Compare(r.path, o.path); c != 0 {
+		return c
+	}
+	return strings.Compare(r.method, o.method)
+}
+
+func (r routeKey) labels() string {
+	return fmt.Sprintf("method=\"%s\",path=\"%s\"", escape(r.method), escape(r.path))
+}
+
+// escape escapes a label value as the text format requires.
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,86 @@
+package prometheus
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"switchbot"
+)
+
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	return rec.Body.String()
+}
+
+func wantLines(t *testing.T, out string, lines ...string) {
+	t.Helper()
+	for _, line := range lines {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("missing %q in:\n%s", line, out)
+		}
+	}
+}
+
+func TestScrape(t *testing.T) {
+	m := New(0.5, 0.1)
+	m.ObserveRequest("GET", "/devices", 200, 50*time.Millisecond)
+	m.ObserveRequest("GET", "/devices", 200, 300*time.Millisecond)
+	m.ObserveRequest("GET", "/devices", 500, 2*time.Second)
+	m.ObserveRetry("GET", "/devices", 1)
+
+	wantLines(t, scrape(t, m),
+		`# TYPE switchbot_requests_total counter`,
+		`switchbot_requests_total{method="GET",path="/devices",code="200"} 2`,
+		`switchbot_requests_total{method="GET",path="/devices",code="500"} 1`,
+		`switchbot_request_errors_total{method="GET",path="/devices",code="500"} 1`,
+		`switchbot_retries_total{method="GET",path="/devices"} 1`,
+		`# TYPE switchbot_request_duration_seconds histogram`,
+		`switchbot_request_duration_seconds_bucket{method="GET",path="/devices",le="0.1"} 1`,
+		`switchbot_request_duration_seconds_bucket{method="GET",path="/devices",le="0.5"} 2`,
+		`switchbot_request_duration_seconds_bucket{method="GET",path="/devices",le="+Inf"} 3`,
+		`switchbot_request_duration_seconds_sum{method="GET",path="/devices"} 2.35`,
+		`switchbot_request_duration_seconds_count{method="GET",path="/devices"} 3`,
+	)
+}
+
+func TestScrapeNoErrorsForSuccess(t *testing.T) {
+	m := New()
+	m.ObserveRequest("GET", "/devices", 200, time.Millisecond)
+	if out := scrape(t, m); strings.Contains(out, "switchbot_request_errors_total{") {
+		t.Errorf("successful request counted as an error:\n%s", out)
+	}
+}
+
+func TestEscape(t *testing.T) {
+	if got := escape("a\"b\\c\nd"); got != `a\"b\\c\nd` {
+		t.Errorf("escape = %s", got)
+	}
+}
+
+func TestWithClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"statusCode":100,"message":"success","body":{}}`)
+	}))
+	defer srv.Close()
+
+	m := New()
+	c, err := switchbot.NewClient("test-token-0123456789abcdef", "test-secret-0123456789abcdef", switchbot.WithBaseURL(srv.URL), switchbot.WithMetrics(m))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.DeviceStatusRaw(context.Background(), "DEV000000001"); err != nil {
+		t.Fatal(err)
+	}
+
+	wantLines(t, scrape(t, m), `switchbot_requests_total{method="GET",path="/devices/{deviceId}/status",code="200"} 1`)
+}
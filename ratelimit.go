@@ -0,0 +1,80 @@
+package switchbot
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket. Tokens may go negative: each waiter takes a
+// token immediately and sleeps until the bucket would have refilled it.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(perSecond float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		rate:   perSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// refill adds the tokens earned since the last call. l.mu must be held.
+func (l *rateLimiter) refill(now time.Time) {
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+}
+
+// Wait blocks until a request may be sent or ctx is done.
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	l.refill(time.Now())
+	l.tokens--
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// Hand the token back so cancelled waiters don't delay others
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// next returns when a request made now would be allowed through, without
+// taking a token.
+func (l *rateLimiter) next(now time.Time) time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	tokens := l.tokens + now.Sub(l.last).Seconds()*l.rate
+	if tokens >= 1 {
+		return now
+	}
+	return now.Add(time.Duration((1 - tokens) / l.rate * float64(time.Second)))
+}
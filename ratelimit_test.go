@@ -0,0 +1,41 @@
+package switchbot
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNextAvailableWithoutLimiter(t *testing.T) {
+	c := newTestClient(t, success)
+	before := time.Now()
+	if next := c.NextAvailable(); next.Before(before) || next.After(time.Now()) {
+		t.Errorf("NextAvailable = %v, want now", next)
+	}
+}
+
+func TestNextAvailableAfterBurst(t *testing.T) {
+	c := newTestClient(t, success, WithRateLimit(1, 2))
+	ctx := context.Background()
+
+	if next := c.NextAvailable(); next.After(time.Now()) {
+		t.Errorf("NextAvailable with a full bucket = %v, want now", next)
+	}
+	for i := 0; i < 2; i++ {
+		if err := c.do(ctx, http.MethodGet, "/devices", nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	wait := time.Until(c.NextAvailable())
+	if wait < 900*time.Millisecond || wait > time.Second {
+		t.Errorf("NextAvailable after exhausting the burst is %v away, want about 1s", wait)
+	}
+
+	// Asking doesn't take a token
+	again := time.Until(c.NextAvailable())
+	if again > wait {
+		t.Errorf("NextAvailable moved from %v to %v without a request", wait, again)
+	}
+}
@@ -0,0 +1,199 @@
+package switchbot
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/netip"
+	"sync"
+	"time"
+)
+
+// maxWebhookBody bounds the size of a delivered webhook event.
+const maxWebhookBody = 1 << 20
+
+// WebhookEvent is an event SwitchBot delivers to a webhook URL.
+type WebhookEvent struct {
+	EventType    string         `json:"eventType"`
+	EventVersion string         `json:"eventVersion"`
+	Context      WebhookContext `json:"context"`
+}
+
+// WebhookContext is the device-specific part of a webhook event. The fields
+// common to all devices are decoded; Raw holds the whole context object, and
+// WebhookEvent.Decode turns it into a typed event.
+type WebhookContext struct {
+	EventBase
+	Raw json.RawMessage `json:"-"`
+}
+
+func (c *WebhookContext) UnmarshalJSON(b []byte) error {
+	type plain WebhookContext
+	if err := json.Unmarshal(b, (*plain)(c)); err != nil {
+		return err
+	}
+	c.Raw = append(json.RawMessage(nil), b...)
+	return nil
+}
+
+// WebhookReceiver is an http.Handler that decodes webhook deliveries and
+// passes them to a handler function.
+type WebhookReceiver struct {
+	handler func(WebhookEvent)
+	dedup   *dedupStore
+	stream  *eventStream
+	allowed []netip.Prefix
+
+	subsMu sync.Mutex
+	subs   map[*subscription]bool
+}
+
+// ReceiverOption configures a WebhookReceiver.
+type ReceiverOption func(*WebhookReceiver)
+
+// WithWebhookDedup drops events that repeat the deviceId, eventType and
+// timeOfSample of an event seen in the last window, since SwitchBot may
+// deliver the same event more than once.
+func WithWebhookDedup(window time.Duration) ReceiverOption {
+	return func(r *WebhookReceiver) {
+		r.dedup = newDedupStore(window, dedupCapacity)
+	}
+}
+
+// WithAllowedSources only accepts deliveries from addresses within the given
+// prefixes, answering 403 to anything else. SwitchBot doesn't sign webhook
+// deliveries, so the source address and an unguessable URL are the only ways
+// to reject spoofed events. The check uses the connection's remote address;
+// behind a reverse proxy, filter at the proxy instead.
+func WithAllowedSources(prefixes ...netip.Prefix) ReceiverOption {
+	return func(r *WebhookReceiver) {
+		r.allowed = append(r.allowed, prefixes...)
+	}
+}
+
+// NewWebhookReceiver returns a receiver calling handler for every event.
+// handler is called synchronously from ServeHTTP, and may be nil when events
+// are consumed through WithEventStream instead.
+func NewWebhookReceiver(handler func(WebhookEvent), opts ...ReceiverOption) *WebhookReceiver {
+	r := &WebhookReceiver{handler: handler}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+func (r *WebhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if len(r.allowed) > 0 && !r.allowedSource(req.RemoteAddr) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxWebhookBody))
+	if err != nil {
+		http.Error(w, "error reading body", http.StatusBadRequest)
+		return
+	}
+
+	var event WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		http.Error(w, "invalid event", http.StatusBadRequest)
+		return
+	}
+
+	// Acknowledge duplicates so SwitchBot stops redelivering them
+	if r.dedup == nil || r.dedup.firstSeen(dedupKey{
+		deviceID:  event.Context.DeviceMac,
+		eventType: event.EventType,
+		timestamp: int64(event.Context.TimeOfSample),
+	}) {
+		if r.handler != nil {
+			r.handler(event)
+		}
+		if r.stream != nil {
+			r.stream.publish(event)
+		}
+		r.fanOut(event)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// allowedSource reports whether remoteAddr falls within an allowed prefix.
+func (r *WebhookReceiver) allowedSource(remoteAddr string) bool {
+	addrPort, err := netip.ParseAddrPort(remoteAddr)
+	if err != nil {
+		return false
+	}
+	addr := addrPort.Addr().Unmap()
+	for _, p := range r.allowed {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// dedupCapacity bounds the number of events remembered for deduplication.
+const dedupCapacity = 4096
+
+type dedupKey struct {
+	deviceID  string
+	eventType string
+	timestamp int64
+}
+
+// dedupStore remembers recently seen events for a fixed window.
+type dedupStore struct {
+	mu       sync.Mutex
+	window   time.Duration
+	capacity int
+	seen     map[dedupKey]time.Time
+}
+
+func newDedupStore(window time.Duration, capacity int) *dedupStore {
+	return &dedupStore{
+		window:   window,
+		capacity: capacity,
+		seen:     make(map[dedupKey]time.Time),
+	}
+}
+
+// firstSeen records key and reports whether it was not already seen within
+// the window.
+func (s *dedupStore) firstSeen(key dedupKey) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if at, ok := s.seen[key]; ok && now.Sub(at) < s.window {
+		return false
+	}
+
+	if len(s.seen) >= s.capacity {
+		s.evict(now)
+	}
+	s.seen[key] = now
+	return true
+}
+
+// evict drops expired entries, and the oldest entry if none had expired.
+// s.mu must be held.
+func (s *dedupStore) evict(now time.Time) {
+	var oldest dedupKey
+	var oldestAt time.Time
+	for k, at := range s.seen {
+		if now.Sub(at) >= s.window {
+			delete(s.seen, k)
+			continue
+		}
+		if oldestAt.IsZero() || at.Before(oldestAt) {
+			oldest, oldestAt = k, at
+		}
+	}
+	if len(s.seen) >= s.capacity {
+		delete(s.seen, oldest)
+	}
+}
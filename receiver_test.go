@@ -0,0 +1,114 @@
+package switchbot
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+)
+
+// eventBody is a changeReport from a contact sensor sampled at ts.
+func eventBody(deviceMac string, ts int64) string {
+	return fmt.Sprintf(`{"eventType":"changeReport","eventVersion":"1","context":{"deviceType":"WoContact","deviceMac":%q,"detectionState":"DETECTED","openState":"open","timeOfSample":%d}}`, deviceMac, ts)
+}
+
+// deliver posts body to h and returns the response status.
+func deliver(h http.Handler, body string) int {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	h.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestWebhookDedup(t *testing.T) {
+	var events []WebhookEvent
+	r := NewWebhookReceiver(func(e WebhookEvent) { events = append(events, e) }, WithWebhookDedup(time.Minute))
+
+	for i := 0; i < 2; i++ {
+		if code := deliver(r, eventBody("DEV000000001", 1700000000000)); code != http.StatusOK {
+			t.Fatalf("delivery %d: status %d, want 200", i, code)
+		}
+	}
+	if len(events) != 1 {
+		t.Fatalf("handled %d events, want the replay dropped", len(events))
+	}
+
+	// A later sample and another device are distinct events
+	deliver(r, eventBody("DEV000000001", 1700000001000))
+	deliver(r, eventBody("DEV000000002", 1700000000000))
+	if len(events) != 3 {
+		t.Errorf("handled %d events, want 3", len(events))
+	}
+}
+
+func TestWebhookWithoutDedup(t *testing.T) {
+	var n int
+	r := NewWebhookReceiver(func(WebhookEvent) { n++ })
+	deliver(r, eventBody("DEV000000001", 1700000000000))
+	deliver(r, eventBody("DEV000000001", 1700000000000))
+	if n != 2 {
+		t.Errorf("handled %d events, want 2 without dedup", n)
+	}
+}
+
+func TestDedupStoreExpiresAndBounds(t *testing.T) {
+	s := newDedupStore(20*time.Millisecond, 2)
+	key := dedupKey{deviceID: "DEV000000001", eventType: "changeReport", timestamp: 1}
+	if !s.firstSeen(key) || s.firstSeen(key) {
+		t.Fatal("second sighting within the window wasn't dropped")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if !s.firstSeen(key) {
+		t.Error("key still deduplicated after the window")
+	}
+
+	for i := int64(2); i < 10; i++ {
+		s.firstSeen(dedupKey{deviceID: "DEV000000001", eventType: "changeReport", timestamp: i})
+	}
+	if len(s.seen) > 2 {
+		t.Errorf("store holds %d keys, want at most 2", len(s.seen))
+	}
+}
+
+func TestWebhookReceiverRejects(t *testing.T) {
+	r := NewWebhookReceiver(func(WebhookEvent) { t.Error("handler called") })
+	if code := deliver(r, "not json"); code != http.StatusBadRequest {
+		t.Errorf("invalid body: status %d, want 400", code)
+	}
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/webhook", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET: status %d, want 405", rec.Code)
+	}
+}
+
+func TestWebhookAllowedSources(t *testing.T) {
+	var n int
+	r := NewWebhookReceiver(func(WebhookEvent) { n++ }, WithAllowedSources(netip.MustParsePrefix("203.0.113.0/24"), netip.MustParsePrefix("2001:db8::/32")))
+
+	tests := []struct {
+		remoteAddr string
+		want       int
+	}{
+		{"203.0.113.7:443", http.StatusOK},
+		{"[::ffff:203.0.113.8]:443", http.StatusOK},
+		{"[2001:db8::1]:443", http.StatusOK},
+		{"198.51.100.1:443", http.StatusForbidden},
+		{"not an address", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(eventBody("DEV000000001", 1700000000000)))
+		req.RemoteAddr = tt.remoteAddr
+		r.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("from %s: status %d, want %d", tt.remoteAddr, rec.Code, tt.want)
+		}
+	}
+	if n != 3 {
+		t.Errorf("handled %d events, want only the 3 from allowed sources", n)
+	}
+}
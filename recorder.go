@@ -0,0 +1,114 @@
+package switchbot
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// redacted replaces credentials in recorded traffic.
+const redacted = "[REDACTED]"
+
+// TrafficEntry is one HTTP attempt as written by WithTrafficRecorder.
+// Bodies that are JSON are embedded as is; others are JSON strings.
+type TrafficEntry struct {
+	Time            time.Time       `json:"time"`
+	Method          string          `json:"method"`
+	URL             string          `json:"url"`
+	RequestHeaders  http.Header     `json:"requestHeaders"`
+	RequestBody     json.RawMessage `json:"requestBody,omitempty"`
+	Status          int             `json:"status"`
+	ResponseHeaders http.Header     `json:"responseHeaders,omitempty"`
+	ResponseBody    json.RawMessage `json:"responseBody,omitempty"`
+	DurationMS      int64           `json:"durationMs"`
+	Error           string          `json:"error,omitempty"`
+}
+
+// trafficRecorder writes TrafficEntry values to w as newline-delimited JSON.
+// A nil *trafficRecorder records nothing.
+type trafficRecorder struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func newTrafficRecorder(w io.Writer) *trafficRecorder {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &trafficRecorder{enc: enc}
+}
+
+// record writes one attempt. resp is nil if no response was received. The
+// Authorization and sign headers are redacted, and the client's token and
+// secret are scrubbed wherever else they appear.
+func (c *Client) record(req *http.Request, reqBody []byte, resp *http.Response, respBody []byte, start time.Time, err error) {
+	rec := c.recorder
+	if rec == nil {
+		return
+	}
+
+	entry := TrafficEntry{
+		Time:           start,
+		Method:         req.Method,
+		URL:            req.URL.String(),
+		RequestHeaders: c.scrubHeader(req.Header),
+		RequestBody:    c.scrubBody(reqBody),
+		DurationMS:     time.Since(start).Milliseconds(),
+	}
+	if resp != nil {
+		entry.Status = resp.StatusCode
+		entry.ResponseHeaders = c.scrubHeader(resp.Header)
+		entry.ResponseBody = c.scrubBody(respBody)
+	}
+	if err != nil {
+		entry.Error = c.scrub(err.Error())
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	// A failing recorder must not fail the request
+	_ = rec.enc.Encode(entry)
+}
+
+func (c *Client) scrubHeader(h http.Header) http.Header {
+	out := make(http.Header, len(h))
+	for key, values := range h {
+		switch http.CanonicalHeaderKey(key) {
+		case "Authorization", "Sign":
+			out[key] = []string{redacted}
+			continue
+		}
+		scrubbed := make([]string, len(values))
+		for i, v := range values {
+			scrubbed[i] = c.scrub(v)
+		}
+		out[key] = scrubbed
+	}
+	return out
+}
+
+// scrubBody returns body with credentials scrubbed, as raw JSON if it is
+// valid JSON and as a JSON string otherwise.
+func (c *Client) scrubBody(body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+	s := c.scrub(string(body))
+	if json.Valid([]byte(s)) {
+		return json.RawMessage(s)
+	}
+	quoted, _ := json.Marshal(s)
+	return quoted
+}
+
+// scrub replaces the client's token and secret in s.
+func (c *Client) scrub(s string) string {
+	for _, secret := range []string{c.token, c.secret} {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, redacted)
+		}
+	}
+	return s
+}
@@ -0,0 +1,138 @@
+package switchbot
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestTrafficRecorder(t *testing.T) {
+	h := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Echo", r.Header.Get("Authorization"))
+		writeEnvelope(w, statusSuccess, map[string]string{"echo": testSecret})
+	}
+	var buf bytes.Buffer
+	c := newTestClient(t, h, WithTrafficRecorder(&buf))
+
+	cmd := Command{Command: "turnOn", Parameter: DefaultParameter}
+	if err := c.do(context.Background(), http.MethodPost, "/devices/BOT01/commands", cmd, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("recorded %d lines, want 1:\n%s", len(lines), buf.String())
+	}
+	if strings.Contains(lines[0], testToken) || strings.Contains(lines[0], testSecret) {
+		t.Errorf("entry leaks credentials: %s", lines[0])
+	}
+
+	var entry TrafficEntry
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.Method != http.MethodPost || !strings.HasSuffix(entry.URL, apiVersion+"/devices/BOT01/commands") || entry.Status != http.StatusOK {
+		t.Errorf("entry = %s %s %d", entry.Method, entry.URL, entry.Status)
+	}
+	for _, key := range []string{"Authorization", "Sign"} {
+		if got := entry.RequestHeaders.Get(key); got != redacted {
+			t.Errorf("request header %s = %q, want %q", key, got, redacted)
+		}
+	}
+	if entry.RequestHeaders.Get("Nonce") == "" || entry.RequestHeaders.Get("T") == "" {
+		t.Error("nonce and t headers not recorded")
+	}
+	if got := entry.ResponseHeaders.Get("X-Echo"); got != redacted {
+		t.Errorf("echoed token = %q, want it scrubbed", got)
+	}
+
+	var body Command
+	if err := json.Unmarshal(entry.RequestBody, &body); err != nil || body != cmd {
+		t.Errorf("request body = %s, want the command as JSON", entry.RequestBody)
+	}
+	var resp struct {
+		Body struct{ Echo string } `json:"body"`
+	}
+	if err := json.Unmarshal(entry.ResponseBody, &resp); err != nil || resp.Body.Echo != redacted {
+		t.Errorf("response body = %s, want JSON with the secret scrubbed", entry.ResponseBody)
+	}
+}
+
+func TestTrafficRecorderNonJSONAndErrors(t *testing.T) {
+	var buf bytes.Buffer
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("<html>bad gateway</html>"))
+	}, WithTrafficRecorder(&buf))
+	if err := c.do(context.Background(), http.MethodGet, "/devices", nil, nil); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	broken, _ := NewClient(testToken, testSecret, WithBaseURL("http://127.0.0.1:1"), WithTrafficRecorder(&buf))
+	if err := broken.do(context.Background(), http.MethodGet, "/devices", nil, nil); err == nil {
+		t.Fatal("expected a transport error")
+	}
+
+	sc := bufio.NewScanner(&buf)
+	var entries []TrafficEntry
+	for sc.Scan() {
+		var e TrafficEntry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			t.Fatalf("line %q: %v", sc.Text(), err)
+		}
+		entries = append(entries, e)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("recorded %d entries, want 2", len(entries))
+	}
+	var html string
+	if err := json.Unmarshal(entries[0].ResponseBody, &html); err != nil || html != "<html>bad gateway</html>" {
+		t.Errorf("HTML body recorded as %s, want a JSON string", entries[0].ResponseBody)
+	}
+	if entries[1].Status != 0 || entries[1].Error == "" || entries[1].ResponseHeaders != nil {
+		t.Errorf("transport error entry = %+v, want no status and the error", entries[1])
+	}
+}
+
+func TestTrafficIndent(t *testing.T) {
+	cmd := Command{Command: "setPosition", Parameter: "0,ff,50", CommandType: CommandTypeCommand}
+	compact, _ := json.Marshal(cmd)
+
+	for _, order := range []string{"indent first", "recorder first"} {
+		var wire []byte
+		h := func(w http.ResponseWriter, r *http.Request) {
+			wire, _ = io.ReadAll(r.Body)
+			success(w, r)
+		}
+		var buf bytes.Buffer
+		opts := []Option{WithTrafficRecorder(&buf), WithTrafficIndent("  ")}
+		if order == "indent first" {
+			opts = []Option{WithTrafficIndent("  "), WithTrafficRecorder(&buf)}
+		}
+		c := newTestClient(t, h, opts...)
+		if err := c.do(context.Background(), http.MethodPost, "/devices/CUR01/commands", cmd, nil); err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(wire, compact) {
+			t.Errorf("%s: sent %s, want compact %s", order, wire, compact)
+		}
+		if !strings.Contains(buf.String(), "\n  \"method\": \"POST\"") {
+			t.Errorf("%s: recording not indented:\n%s", order, buf.String())
+		}
+		var entry TrafficEntry
+		if err := json.NewDecoder(&buf).Decode(&entry); err != nil {
+			t.Fatalf("%s: %v", order, err)
+		}
+		var body Command
+		if err := json.Unmarshal(entry.RequestBody, &body); err != nil || body != cmd {
+			t.Errorf("%s: recorded body %s, want the command", order, entry.RequestBody)
+		}
+	}
+}
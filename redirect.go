@@ -0,0 +1,29 @@
+package switchbot
+
+import (
+	"errors"
+	"net/http"
+)
+
+// maxRedirects matches the limit of the default http.Client policy.
+const maxRedirects = 10
+
+// securityHeaders are the signed headers added by createHeaders. Together
+// they are enough to replay a request, so they must not reach another host.
+var securityHeaders = []string{"Authorization", "sign", "t", "nonce"}
+
+// StripCredentialsOnRedirect is an http.Client CheckRedirect policy that
+// drops the signed security headers when a redirect leaves the original host.
+// The default client uses it; set it on a client passed to WithHTTPClient to
+// get the same protection.
+func StripCredentialsOnRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return errors.New("stopped after 10 redirects")
+	}
+	if req.URL.Host != via[0].URL.Host {
+		for _, h := range securityHeaders {
+			req.Header.Del(h)
+		}
+	}
+	return nil
+}
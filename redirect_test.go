@@ -0,0 +1,53 @@
+package switchbot
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRedirectStripsCredentialsAcrossHosts(t *testing.T) {
+	var got http.Header
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		success(w, r)
+	}))
+	defer other.Close()
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, other.URL+r.URL.Path, http.StatusFound)
+	})
+	if err := c.do(context.Background(), http.MethodGet, "/devices", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if got == nil {
+		t.Fatal("redirect not followed")
+	}
+	for _, h := range securityHeaders {
+		if v := got.Get(h); v != "" {
+			t.Errorf("%s header %q reached the other host", h, v)
+		}
+	}
+}
+
+func TestRedirectKeepsCredentialsOnSameHost(t *testing.T) {
+	var got http.Header
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == apiVersion+"/devices" {
+			http.Redirect(w, r, apiVersion+"/moved", http.StatusFound)
+			return
+		}
+		got = r.Header.Clone()
+		success(w, r)
+	})
+	if err := c.do(context.Background(), http.MethodGet, "/devices", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	for _, h := range securityHeaders {
+		if got.Get(h) == "" {
+			t.Errorf("%s header dropped on a same-host redirect", h)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package switchbot
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWithRegion(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{"default", nil, DefaultBaseURL},
+		{"global", []Option{WithRegion(RegionGlobal)}, DefaultBaseURL},
+		{"case and spaces", []Option{WithRegion(" Global ")}, DefaultBaseURL},
+		{"base URL after region", []Option{WithRegion(RegionGlobal), WithBaseURL("http://localhost:8080/")}, "http://localhost:8080"},
+		{"base URL before region", []Option{WithBaseURL("http://localhost:8080"), WithRegion("mars")}, "http://localhost:8080"},
+	}
+	for _, tt := range tests {
+		c, err := NewClient(testToken, testSecret, tt.opts...)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if c.baseURL != tt.want {
+			t.Errorf("%s: base URL = %q, want %q", tt.name, c.baseURL, tt.want)
+		}
+	}
+}
+
+func TestWithRegionUnknown(t *testing.T) {
+	if _, err := NewClient(testToken, testSecret, WithRegion("mars")); !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("err = %v, want ErrInvalidParameter", err)
+	}
+}
@@ -0,0 +1,70 @@
+package switchbot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// IR remote categories, as reported in remoteType.
+const (
+	RemoteTypeAirConditioner = "Air Conditioner"
+	RemoteTypeTV             = "TV"
+	RemoteTypeLight          = "Light"
+	RemoteTypeStreamer       = "IPTV/Streamer"
+	RemoteTypeSetTopBox      = "Set Top Box"
+	RemoteTypeDVD            = "DVD"
+	RemoteTypeFan            = "Fan"
+	RemoteTypeProjector      = "Projector"
+	RemoteTypeCamera         = "Camera"
+	RemoteTypeAirPurifier    = "Air Purifier"
+	RemoteTypeSpeaker        = "Speaker"
+	RemoteTypeWaterHeater    = "Water Heater"
+	RemoteTypeVacuumCleaner  = "Vacuum Cleaner"
+	RemoteTypeOthers         = "Others"
+)
+
+// diyPrefix marks remotes whose buttons were learned one by one rather than
+// picked from SwitchBot's database, e.g. "DIY TV".
+const diyPrefix = "DIY "
+
+// NormalizeRemoteType maps a DIY variant of a remote type to its base
+// category, so "DIY TV" becomes "TV". Other types are returned unchanged.
+func NormalizeRemoteType(remoteType string) string {
+	return strings.TrimPrefix(remoteType, diyPrefix)
+}
+
+// IRRemotesByCategory lists the IR remotes whose normalized remote type
+// matches category, ignoring case, with their annotations. DIY remotes are
+// included with their base category.
+func (c *Client) IRRemotesByCategory(ctx context.Context, category string) ([]InfraredRemote, error) {
+	list, err := c.deviceList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	category = NormalizeRemoteType(category)
+	var remotes []InfraredRemote
+	for _, r := range list.InfraredRemoteList {
+		if strings.EqualFold(NormalizeRemoteType(r.RemoteType), category) {
+			remotes = append(remotes, r)
+		}
+	}
+	c.annotateRemotes(remotes)
+	return remotes, nil
+}
+
+// SendCustomIRButton presses a user-defined button, named as in the
+// SwitchBot app, on an IR remote; it is sent with CommandTypeCustomize. The
+// API doesn't list a remote's custom buttons, so the caller must know the
+// name.
+func (c *Client) SendCustomIRButton(ctx context.Context, remoteID, button string) error {
+	if strings.TrimSpace(button) == "" {
+		return fmt.Errorf("%w: custom button name must not be empty", ErrInvalidParameter)
+	}
+	return c.SendCommand(ctx, remoteID, Command{
+		Command:     button,
+		Parameter:   DefaultParameter,
+		CommandType: CommandTypeCustomize,
+	})
+}
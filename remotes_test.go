@@ -0,0 +1,93 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestSendCustomIRButton(t *testing.T) {
+	c, srv := newCommandClient(t)
+	ctx := context.Background()
+
+	if err := c.SendCustomIRButton(ctx, "02-000000000000-00000002", "Movie Mode"); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"", "  "} {
+		if err := c.SendCustomIRButton(ctx, "02-000000000000-00000002", name); !errors.Is(err, ErrInvalidParameter) {
+			t.Errorf("button %q: err = %v, want ErrInvalidParameter", name, err)
+		}
+	}
+	wantCommands(t, srv.commands(), sentCommand{"02-000000000000-00000002", Command{
+		Command:     "Movie Mode",
+		Parameter:   DefaultParameter,
+		CommandType: CommandTypeCustomize,
+	}})
+}
+
+func TestNormalizeRemoteType(t *testing.T) {
+	tests := []struct {
+		remoteType, want string
+	}{
+		{"TV", "TV"},
+		{"DIY TV", "TV"},
+		{"DIY Air Conditioner", "Air Conditioner"},
+		{"Air Conditioner", "Air Conditioner"},
+		{"DIY", "DIY"},
+		{"Others", "Others"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeRemoteType(tt.remoteType); got != tt.want {
+			t.Errorf("NormalizeRemoteType(%q) = %q, want %q", tt.remoteType, got, tt.want)
+		}
+	}
+}
+
+func TestIRRemotesByCategory(t *testing.T) {
+	remotes := []InfraredRemote{
+		{DeviceID: "TV01", RemoteType: "TV"},
+		{DeviceID: "AC01", RemoteType: "Air Conditioner"},
+		{DeviceID: "TV02", RemoteType: "DIY TV"},
+		{DeviceID: "FAN01", RemoteType: "DIY Fan"},
+	}
+	c, _ := newDeviceClient(t, nil, remotes)
+	ctx := context.Background()
+
+	tests := []struct {
+		category string
+		want     []string
+	}{
+		{"TV", []string{"TV01", "TV02"}},
+		{"tv", []string{"TV01", "TV02"}},
+		{"DIY TV", []string{"TV01", "TV02"}},
+		{"Fan", []string{"FAN01"}},
+		{"Light", nil},
+	}
+	for _, tt := range tests {
+		got, err := c.IRRemotesByCategory(ctx, tt.category)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, r := range got {
+			ids = append(ids, r.DeviceID)
+		}
+		if !slices.Equal(ids, tt.want) {
+			t.Errorf("IRRemotesByCategory(%q) = %v, want %v", tt.category, ids, tt.want)
+		}
+	}
+}
+
+func TestIRRemotesByCategoryFixture(t *testing.T) {
+	c := newTestClient(t, serveFixture(t, "devices"))
+
+	tvs, err := c.IRRemotesByCategory(context.Background(), "TV")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tvs) != 1 || tvs[0].DeviceName != "Bedroom TV" || tvs[0].RemoteType != "DIY TV" {
+		t.Errorf("TV remotes = %+v, want the DIY TV remote with its type unchanged", tvs)
+	}
+}
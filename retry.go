@@ -0,0 +1,63 @@
+package switchbot
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// maxBackoff caps the delay between retries.
+const maxBackoff = 30 * time.Second
+
+// Jitter randomises an exponential backoff delay d so that many clients
+// failing at once don't retry in lockstep. int63n returns a random number in
+// [0, n), as rand.Int63n does; passing a seeded source makes the result
+// reproducible.
+type Jitter func(d time.Duration, int63n func(n int64) int64) time.Duration
+
+// FullJitter waits a random time between 0 and d. It spreads retries the
+// most and is the default.
+func FullJitter(d time.Duration, int63n func(n int64) int64) time.Duration {
+	return time.Duration(int63n(int64(d) + 1))
+}
+
+// EqualJitter waits at least half of d, plus a random part of the other
+// half, keeping a minimum delay while still spreading retries.
+func EqualJitter(d time.Duration, int63n func(n int64) int64) time.Duration {
+	half := d / 2
+	return half + time.Duration(int63n(int64(d-half)+1))
+}
+
+// NoJitter waits exactly d.
+func NoJitter(d time.Duration, _ func(n int64) int64) time.Duration {
+	return d
+}
+
+// backoff returns how long to wait before the given retry attempt (1 for the
+// first retry), using exponential backoff with the configured jitter.
+func (c *Client) backoff(attempt int) time.Duration {
+	// Double by steps rather than shifting by attempt-1 so a large attempt
+	// can't overflow. A zero base delay retries immediately.
+	d := max(c.retryBaseDelay, 0)
+	for i := 1; i < attempt && d < maxBackoff; i++ {
+		d <<= 1
+	}
+	d = min(d, maxBackoff)
+	jitter := c.jitter
+	if jitter == nil {
+		jitter = FullJitter
+	}
+	return jitter(d, rand.Int63n)
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
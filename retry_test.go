@@ -0,0 +1,86 @@
+package switchbot
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		base    time.Duration
+		attempt int
+		want    time.Duration
+	}{
+		{0, 1, 0},
+		{0, 10, 0},
+		{100 * time.Millisecond, 1, 100 * time.Millisecond},
+		{100 * time.Millisecond, 3, 400 * time.Millisecond},
+		{100 * time.Millisecond, 20, maxBackoff},
+		{time.Second, 100, maxBackoff},
+		{time.Minute, 1, maxBackoff},
+	}
+	for _, tt := range tests {
+		c := &Client{retryBaseDelay: tt.base, jitter: NoJitter}
+		if got := c.backoff(tt.attempt); got != tt.want {
+			t.Errorf("backoff(%d) with base %v = %v, want %v", tt.attempt, tt.base, got, tt.want)
+		}
+	}
+}
+
+func TestJitterBounds(t *testing.T) {
+	const d = time.Second
+	tests := []struct {
+		name     string
+		jitter   Jitter
+		min, max time.Duration
+	}{
+		{"full", FullJitter, 0, d},
+		{"equal", EqualJitter, d / 2, d},
+		{"none", NoJitter, d, d},
+	}
+	for _, tt := range tests {
+		rng := rand.New(rand.NewSource(1))
+		var lo, hi time.Duration = d, 0
+		for i := 0; i < 1000; i++ {
+			got := tt.jitter(d, rng.Int63n)
+			if got < tt.min || got > tt.max {
+				t.Fatalf("%s: %v outside [%v, %v]", tt.name, got, tt.min, tt.max)
+			}
+			lo, hi = min(lo, got), max(hi, got)
+		}
+		if tt.min != tt.max && hi-lo < (tt.max-tt.min)/2 {
+			t.Errorf("%s: delays only spread over [%v, %v]", tt.name, lo, hi)
+		}
+	}
+}
+
+func TestJitterSeededReproducible(t *testing.T) {
+	a, b := rand.New(rand.NewSource(42)), rand.New(rand.NewSource(42))
+	for i := 0; i < 10; i++ {
+		if x, y := FullJitter(time.Second, a.Int63n), FullJitter(time.Second, b.Int63n); x != y {
+			t.Fatalf("same seed gave %v and %v", x, y)
+		}
+	}
+}
+
+func TestWithBackoffJitter(t *testing.T) {
+	c, err := NewClient(testToken, testSecret, WithRetry(3, 100*time.Millisecond), WithBackoffJitter(NoJitter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.backoff(2); got != 200*time.Millisecond {
+		t.Errorf("backoff(2) with NoJitter = %v, want 200ms", got)
+	}
+
+	// The default is full jitter: never more than the exponential delay
+	c, err = NewClient(testToken, testSecret, WithRetry(3, 100*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		if got := c.backoff(2); got < 0 || got > 200*time.Millisecond {
+			t.Fatalf("default backoff(2) = %v, want within [0, 200ms]", got)
+		}
+	}
+}
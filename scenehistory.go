@@ -0,0 +1,65 @@
+package switchbot
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// DefaultSceneHistoryCapacity is the number of executions kept by
+// WithSceneHistory(0).
+const DefaultSceneHistoryCapacity = 100
+
+// SceneExecution is one ExecuteScene call, as returned by SceneHistory.
+type SceneExecution struct {
+	SceneID string
+	At      time.Time
+	// Err is the error ExecuteScene returned, nil if SwitchBot accepted the
+	// request. As with ExecuteScene, success means the scene started, not
+	// that its actions took effect.
+	Err error
+}
+
+// sceneHistory is a bounded log of scene executions, dropping the oldest
+// once full. A nil *sceneHistory records nothing.
+type sceneHistory struct {
+	mu       sync.Mutex
+	capacity int
+	entries  []SceneExecution
+}
+
+func newSceneHistory(capacity int) *sceneHistory {
+	if capacity < 1 {
+		capacity = DefaultSceneHistoryCapacity
+	}
+	return &sceneHistory{capacity: capacity}
+}
+
+func (h *sceneHistory) add(e SceneExecution) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.entries) >= h.capacity {
+		h.entries = slices.Delete(h.entries, 0, len(h.entries)-h.capacity+1)
+	}
+	h.entries = append(h.entries, e)
+}
+
+func (h *sceneHistory) list() []SceneExecution {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return slices.Clone(h.entries)
+}
+
+// SceneHistory returns the scenes run with ExecuteScene, or a helper built on
+// it, oldest first. SwitchBot doesn't expose an execution log, so this only
+// covers executions by this client since it was created, and needs
+// WithSceneHistory; otherwise it returns nil.
+func (c *Client) SceneHistory() []SceneExecution {
+	return c.sceneHistory.list()
+}
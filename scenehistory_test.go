@@ -0,0 +1,61 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestSceneHistory(t *testing.T) {
+	c := newTestClient(t, sceneServer(http.StatusOK, statusSceneNotFound), WithSceneHistory(0))
+	ctx := context.Background()
+	before := time.Now()
+
+	if _, err := c.ExecuteScene(ctx, "SCENE01"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ExecuteScene(ctx, "MISSING"); !errors.Is(err, ErrSceneNotFound) {
+		t.Fatalf("err = %v, want ErrSceneNotFound", err)
+	}
+
+	history := c.SceneHistory()
+	if len(history) != 2 {
+		t.Fatalf("history = %+v, want two executions", history)
+	}
+	if history[0].SceneID != "SCENE01" || history[0].Err != nil {
+		t.Errorf("first execution = %+v, want SCENE01 succeeding", history[0])
+	}
+	if history[1].SceneID != "MISSING" || !errors.Is(history[1].Err, ErrSceneNotFound) {
+		t.Errorf("second execution = %+v, want MISSING failing", history[1])
+	}
+	if history[0].At.Before(before) || history[1].At.Before(history[0].At) {
+		t.Errorf("timestamps %v, %v not in order", history[0].At, history[1].At)
+	}
+}
+
+func TestSceneHistoryBounded(t *testing.T) {
+	h := newSceneHistory(2)
+	for _, id := range []string{"A", "B", "C"} {
+		h.add(SceneExecution{SceneID: id})
+	}
+	got := h.list()
+	if len(got) != 2 || got[0].SceneID != "B" || got[1].SceneID != "C" {
+		t.Errorf("history = %+v, want the two newest", got)
+	}
+	got[0].SceneID = "changed"
+	if h.list()[0].SceneID != "B" {
+		t.Error("list returned the shared slice")
+	}
+}
+
+func TestSceneHistoryDisabled(t *testing.T) {
+	c := newTestClient(t, sceneServer(http.StatusOK, statusSceneNotFound))
+	if _, err := c.ExecuteScene(context.Background(), "SCENE01"); err != nil {
+		t.Fatal(err)
+	}
+	if h := c.SceneHistory(); h != nil {
+		t.Errorf("history = %+v without WithSceneHistory, want nil", h)
+	}
+}
@@ -0,0 +1,187 @@
+package switchbot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrSceneNotFound is returned when a scene id or name matches no scene.
+	ErrSceneNotFound = errors.New("scene not found")
+
+	// ErrAmbiguousScene is returned when a name matches more than one scene.
+	ErrAmbiguousScene = errors.New("scene name is ambiguous")
+)
+
+// Scene is a manual scene configured in the SwitchBot app.
+type Scene struct {
+	SceneID   string `json:"sceneId"`
+	SceneName string `json:"sceneName"`
+}
+
+// SceneResult confirms a scene was started. SwitchBot only acknowledges the
+// request: a success means the scene began running, not that every action in
+// it succeeded.
+type SceneResult struct {
+	SceneID string
+	// Message is the envelope message, normally "success".
+	Message string
+	// Body is whatever the API returned beyond the envelope, usually {}.
+	Body json.RawMessage
+}
+
+// Scenes lists the manual scenes on the account.
+func (c *Client) Scenes(ctx context.Context) ([]Scene, error) {
+	var scenes []Scene
+	if err := c.do(ctx, http.MethodGet, "/scenes", nil, &scenes); err != nil {
+		return nil, err
+	}
+	return scenes, nil
+}
+
+// statusSceneNotFound is the envelope statusCode SwitchBot answers a scene
+// execution with when the scene id is unknown. For device commands the same
+// code means a device error, so it is only mapped for scenes.
+const statusSceneNotFound = 190
+
+// ExecuteScene starts the scene with the given id. An unknown id, reported by
+// the API as statusCode 190 or an HTTP 404, is returned as ErrSceneNotFound
+// wrapping the underlying error; other errors are returned as is. With
+// WithSceneHistory every call is logged for SceneHistory.
+func (c *Client) ExecuteScene(ctx context.Context, id string) (*SceneResult, error) {
+	var body json.RawMessage
+	r := &apiRequest{
+		method: http.MethodPost,
+		path:   "/scenes/" + url.PathEscape(id) + "/execute",
+		out:    &body,
+	}
+	err := c.send(ctx, r)
+	var httpErr *HTTPError
+	var apiErr *APIError
+	if (errors.As(err, &apiErr) && apiErr.StatusCode == statusSceneNotFound) ||
+		(errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound) {
+		err = fmt.Errorf("%w: %s: %w", ErrSceneNotFound, id, err)
+	}
+	c.sceneHistory.add(SceneExecution{SceneID: id, At: time.Now(), Err: err})
+	if err != nil {
+		return nil, err
+	}
+	return &SceneResult{SceneID: id, Message: r.respMessage, Body: body}, nil
+}
+
+// ExecuteSceneByName starts the scene with the given name, matched
+// case-insensitively. It returns ErrSceneNotFound if no scene has the name
+// and ErrAmbiguousScene if several do. With WithDeviceCache the scene list is
+// cached for the same ttl as the device list.
+func (c *Client) ExecuteSceneByName(ctx context.Context, name string) (*SceneResult, error) {
+	scenes, err := c.cachedScenes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, s := range scenes {
+		if strings.EqualFold(s.SceneName, name) {
+			ids = append(ids, s.SceneID)
+		}
+	}
+	switch len(ids) {
+	case 0:
+		return nil, fmt.Errorf("%w: %q", ErrSceneNotFound, name)
+	case 1:
+		return c.ExecuteScene(ctx, ids[0])
+	default:
+		return nil, fmt.Errorf("%w: %q matches %s", ErrAmbiguousScene, name, strings.Join(ids, ", "))
+	}
+}
+
+// sceneCache holds the scene list for WithDeviceCache.
+type sceneCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	scenes  []Scene
+	fetched time.Time
+}
+
+// cachedScenes returns the scene list, from the cache if one is configured
+// and still fresh.
+func (c *Client) cachedScenes(ctx context.Context) ([]Scene, error) {
+	if c.scenes == nil {
+		return c.Scenes(ctx)
+	}
+
+	c.scenes.mu.Lock()
+	defer c.scenes.mu.Unlock()
+
+	if c.scenes.scenes != nil && time.Since(c.scenes.fetched) < c.scenes.ttl {
+		return c.scenes.scenes, nil
+	}
+	scenes, err := c.Scenes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if scenes == nil {
+		scenes = []Scene{}
+	}
+	c.scenes.scenes = scenes
+	c.scenes.fetched = time.Now()
+	return scenes, nil
+}
+
+// ErrSceneUnconfirmed is returned by ExecuteSceneAndWait when some of its
+// checks didn't pass in time.
+var ErrSceneUnconfirmed = errors.New("scene effects not confirmed")
+
+// StateCheck is a device state a scene is expected to produce. Cond is given
+// the typed status of DeviceID, as returned by DeviceStatus.
+type StateCheck struct {
+	DeviceID string
+	// Name describes the check in errors, e.g. "hall light on".
+	Name string
+	Cond func(status any) bool
+}
+
+// ExecuteSceneAndWait runs a scene, then polls the devices in checks until
+// every check passes or timeout elapses. It returns the checks that didn't
+// pass, along with ErrSceneUnconfirmed, if any.
+//
+// Confirmation is best-effort: scenes may contain delays, and SwitchBot
+// reports state changes with some lag, so timeout should cover both.
+func (c *Client) ExecuteSceneAndWait(ctx context.Context, sceneID string, checks []StateCheck, timeout time.Duration) ([]StateCheck, error) {
+	if _, err := c.ExecuteScene(ctx, sceneID); err != nil {
+		return nil, err
+	}
+
+	// One deadline for all checks, however many run at once
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	passed := make([]bool, len(checks))
+	c.forEach(len(checks), func(i int) {
+		_, err := c.WaitForState(ctx, checks[i].DeviceID, checks[i].Cond, 0)
+		passed[i] = err == nil
+	})
+
+	var failed []StateCheck
+	var names []string
+	for i, ok := range passed {
+		if !ok {
+			failed = append(failed, checks[i])
+			names = append(names, checks[i].Name)
+		}
+	}
+	if len(failed) > 0 {
+		return failed, fmt.Errorf("%w: %s: %s", ErrSceneUnconfirmed, sceneID, strings.Join(names, ", "))
+	}
+	return nil, nil
+}
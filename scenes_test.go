@@ -0,0 +1,207 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sceneServer lists and executes one scene, SCENE01. Executing any other
+// scene fails with HTTP status httpStatus or, if that is 200, with envelope
+// statusCode.
+func sceneServer(httpStatus, statusCode int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case apiVersion + "/scenes":
+			writeEnvelope(w, statusSuccess, []Scene{{SceneID: "SCENE01", SceneName: "Good Night"}})
+		case apiVersion + "/scenes/SCENE01/execute":
+			writeEnvelope(w, statusSuccess, struct{}{})
+		default:
+			if httpStatus != http.StatusOK {
+				w.WriteHeader(httpStatus)
+				return
+			}
+			writeAPIError(w, statusCode, "error")
+		}
+	}
+}
+
+func TestExecuteScene(t *testing.T) {
+	c := newTestClient(t, sceneServer(http.StatusOK, statusSceneNotFound))
+
+	result, err := c.ExecuteScene(context.Background(), "SCENE01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.SceneID != "SCENE01" || result.Message != "success" || string(result.Body) != "{}" {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestExecuteSceneNotFound(t *testing.T) {
+	tests := []struct {
+		name                   string
+		httpStatus, statusCode int
+	}{
+		{"statusCode 190", http.StatusOK, statusSceneNotFound},
+		{"HTTP 404", http.StatusNotFound, 0},
+	}
+	for _, tt := range tests {
+		c := newTestClient(t, sceneServer(tt.httpStatus, tt.statusCode))
+		_, err := c.ExecuteScene(context.Background(), "MISSING")
+		if !errors.Is(err, ErrSceneNotFound) {
+			t.Errorf("%s: err = %v, want ErrSceneNotFound", tt.name, err)
+		}
+	}
+}
+
+func TestExecuteSceneOtherErrors(t *testing.T) {
+	tests := []struct {
+		name                   string
+		httpStatus, statusCode int
+	}{
+		{"statusCode 161", http.StatusOK, 161},
+		{"HTTP 401", http.StatusUnauthorized, 0},
+		{"HTTP 500", http.StatusInternalServerError, 0},
+	}
+	for _, tt := range tests {
+		c := newTestClient(t, sceneServer(tt.httpStatus, tt.statusCode))
+		_, err := c.ExecuteScene(context.Background(), "MISSING")
+		if err == nil || errors.Is(err, ErrSceneNotFound) {
+			t.Errorf("%s: err = %v, want an error other than ErrSceneNotFound", tt.name, err)
+		}
+		var apiErr *APIError
+		var httpErr *HTTPError
+		if !errors.As(err, &apiErr) && !errors.As(err, &httpErr) {
+			t.Errorf("%s: err = %v, want the API error returned as is", tt.name, err)
+		}
+	}
+}
+
+func TestExecuteSceneByName(t *testing.T) {
+	c := newTestClient(t, sceneServer(http.StatusOK, statusSceneNotFound))
+	ctx := context.Background()
+
+	if _, err := c.ExecuteSceneByName(ctx, "good night"); err != nil {
+		t.Errorf("case-insensitive name: %v", err)
+	}
+	if _, err := c.ExecuteSceneByName(ctx, "Morning"); !errors.Is(err, ErrSceneNotFound) {
+		t.Errorf("unknown name: err = %v, want ErrSceneNotFound", err)
+	}
+}
+
+func TestExecuteSceneByNameAmbiguous(t *testing.T) {
+	var executed []string
+	h := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == apiVersion+"/scenes" {
+			writeEnvelope(w, statusSuccess, []Scene{
+				{SceneID: "SCENE01", SceneName: "Leave home"},
+				{SceneID: "SCENE02", SceneName: "leave home"},
+				{SceneID: "SCENE03", SceneName: "Arrive home"},
+			})
+			return
+		}
+		executed = append(executed, r.URL.Path)
+		writeEnvelope(w, statusSuccess, struct{}{})
+	}
+	c := newTestClient(t, h)
+
+	_, err := c.ExecuteSceneByName(context.Background(), "Leave Home")
+	if !errors.Is(err, ErrAmbiguousScene) {
+		t.Fatalf("err = %v, want ErrAmbiguousScene", err)
+	}
+	if !strings.Contains(err.Error(), "SCENE01") || !strings.Contains(err.Error(), "SCENE02") {
+		t.Errorf("error %q doesn't name the matching scenes", err)
+	}
+	if len(executed) != 0 {
+		t.Errorf("executed %v, want nothing for an ambiguous name", executed)
+	}
+}
+
+func TestExecuteSceneByNameCachesScenes(t *testing.T) {
+	var lists int
+	inner := sceneServer(http.StatusOK, statusSceneNotFound)
+	h := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == apiVersion+"/scenes" {
+			lists++
+		}
+		inner(w, r)
+	}
+	c := newTestClient(t, h, WithDeviceCache(time.Hour))
+	for i := 0; i < 3; i++ {
+		if _, err := c.ExecuteSceneByName(context.Background(), "Good Night"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if lists != 1 {
+		t.Errorf("scene list fetched %d times, want 1 with WithDeviceCache", lists)
+	}
+}
+
+// confirmServer executes SCENE01 and reports BOT01 as on and PLUG01 as off.
+func confirmServer(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case apiVersion + "/scenes/SCENE01/execute":
+		writeEnvelope(w, statusSuccess, struct{}{})
+	case apiVersion + "/devices/BOT01/status":
+		writeEnvelope(w, statusSuccess, map[string]any{"deviceId": "BOT01", "deviceType": "Bot", "power": "on"})
+	case apiVersion + "/devices/PLUG01/status":
+		writeEnvelope(w, statusSuccess, map[string]any{"deviceId": "PLUG01", "deviceType": "Plug Mini (US)", "power": "off"})
+	default:
+		writeAPIError(w, statusSceneNotFound, "scene not found")
+	}
+}
+
+// deviceOn is a StateCheck condition for a bot or plug being on.
+func deviceOn(status any) bool {
+	switch s := status.(type) {
+	case *BotStatus:
+		return s.Power == PowerOn
+	case *PlugStatus:
+		return s.Power == PowerOn
+	}
+	return false
+}
+
+func TestExecuteSceneAndWait(t *testing.T) {
+	c := newTestClient(t, confirmServer, WithPollInterval(time.Millisecond))
+	checks := []StateCheck{{DeviceID: "BOT01", Name: "kettle on", Cond: deviceOn}}
+
+	failed, err := c.ExecuteSceneAndWait(context.Background(), "SCENE01", checks, time.Second)
+	if err != nil || failed != nil {
+		t.Errorf("failed = %+v, err = %v, want every check confirmed", failed, err)
+	}
+}
+
+func TestExecuteSceneAndWaitUnconfirmed(t *testing.T) {
+	c := newTestClient(t, confirmServer, WithPollInterval(5*time.Millisecond))
+	checks := []StateCheck{
+		{DeviceID: "BOT01", Name: "kettle on", Cond: deviceOn},
+		{DeviceID: "PLUG01", Name: "lamp on", Cond: deviceOn},
+	}
+
+	start := time.Now()
+	failed, err := c.ExecuteSceneAndWait(context.Background(), "SCENE01", checks, 50*time.Millisecond)
+	if !errors.Is(err, ErrSceneUnconfirmed) || !strings.Contains(err.Error(), "lamp on") {
+		t.Errorf("err = %v, want ErrSceneUnconfirmed naming the lamp check", err)
+	}
+	if len(failed) != 1 || failed[0].DeviceID != "PLUG01" {
+		t.Errorf("failed = %+v, want only the plug check", failed)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("returned after %v, want about the 50ms timeout", elapsed)
+	}
+}
+
+func TestExecuteSceneAndWaitExecuteError(t *testing.T) {
+	c := newTestClient(t, confirmServer)
+	checks := []StateCheck{{DeviceID: "BOT01", Name: "kettle on", Cond: deviceOn}}
+
+	failed, err := c.ExecuteSceneAndWait(context.Background(), "MISSING", checks, time.Second)
+	if !errors.Is(err, ErrSceneNotFound) || failed != nil {
+		t.Errorf("failed = %+v, err = %v, want ErrSceneNotFound and no checks run", failed, err)
+	}
+}
@@ -0,0 +1,139 @@
+package switchbot
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ScheduledCommand is a command waiting to be sent by ScheduleCommand.
+type ScheduledCommand struct {
+	ID       string
+	DeviceID string
+	Command  Command
+	At       time.Time
+}
+
+// afterFunc waits for d to pass and then calls f in its own goroutine, like
+// time.AfterFunc, returning the function that stops the timer.
+type afterFunc func(d time.Duration, f func()) (stop func() bool)
+
+// scheduler holds the pending commands of a Client. The zero value is ready
+// to use.
+type scheduler struct {
+	mu      sync.Mutex
+	next    int
+	pending map[string]*pendingCommand
+
+	// now and afterFunc replace time.Now and time.AfterFunc if afterFunc
+	// is set, for tests to drive schedules with a fake clock.
+	now       func() time.Time
+	afterFunc afterFunc
+}
+
+type pendingCommand struct {
+	ScheduledCommand
+	stopTimer func() bool
+	stop      func() bool // stops watching the schedule's context
+}
+
+// withSchedulerClock makes ScheduleCommand read the time from now and wait
+// with after instead of using the real clock.
+func withSchedulerClock(now func() time.Time, after afterFunc) Option {
+	return func(c *Client) {
+		c.schedules.now, c.schedules.afterFunc = now, after
+	}
+}
+
+// ScheduleCommand sends cmd to the device at the given time, from a
+// background goroutine, and returns the pending schedule. It is meant for
+// simple needs such as "turn off in 30 minutes" within one process: pending
+// commands are lost when the process exits.
+//
+// The command is sent with ctx, through the rate limiter and cooldown like
+// any other, so ctx must outlive the wait; cancelling ctx cancels the
+// schedule. A time in the past sends the command straight away. Send errors
+// are logged when WithLogger is set and recorded by WithCommandResults.
+func (c *Client) ScheduleCommand(ctx context.Context, at time.Time, id string, cmd Command) ScheduledCommand {
+	s := &c.schedules
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.pending == nil {
+		s.pending = make(map[string]*pendingCommand)
+	}
+	s.next++
+	p := &pendingCommand{ScheduledCommand: ScheduledCommand{
+		ID:       "schedule-" + strconv.Itoa(s.next),
+		DeviceID: id,
+		Command:  cmd,
+		At:       at,
+	}}
+	s.pending[p.ID] = p
+
+	p.stop = context.AfterFunc(ctx, func() { c.CancelSchedule(p.ID) })
+	p.stopTimer = s.start(at, func() {
+		if !s.remove(p.ID) {
+			return
+		}
+		p.stop()
+		if err := c.SendCommand(ctx, id, cmd); err != nil && c.logger != nil {
+			c.logger.WarnContext(ctx, "switchbot scheduled command failed", "schedule", p.ID, "device_id", id, "command", cmd.Command, "error", err)
+		}
+	})
+	return p.ScheduledCommand
+}
+
+// PendingSchedules returns the commands not yet sent, soonest first.
+func (c *Client) PendingSchedules() []ScheduledCommand {
+	s := &c.schedules
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending := make([]ScheduledCommand, 0, len(s.pending))
+	for _, p := range s.pending {
+		pending = append(pending, p.ScheduledCommand)
+	}
+	slices.SortFunc(pending, func(a, b ScheduledCommand) int {
+		return a.At.Compare(b.At)
+	})
+	return pending
+}
+
+// CancelSchedule cancels a pending command. It reports false if the command
+// has already been sent or cancelled.
+func (c *Client) CancelSchedule(scheduleID string) bool {
+	s := &c.schedules
+	s.mu.Lock()
+	p, ok := s.pending[scheduleID]
+	delete(s.pending, scheduleID)
+	s.mu.Unlock()
+
+	if !ok {
+		return false
+	}
+	p.stopTimer()
+	p.stop()
+	return true
+}
+
+// remove takes a schedule off the pending list, reporting whether it was
+// still there.
+func (s *scheduler) remove(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.pending[id]
+	delete(s.pending, id)
+	return ok
+}
+
+// start calls f once at has been reached, and returns the function that
+// stops the timer.
+func (s *scheduler) start(at time.Time, f func()) func() bool {
+	if s.afterFunc != nil {
+		return s.afterFunc(at.Sub(s.now()), f)
+	}
+	return time.AfterFunc(time.Until(at), f).Stop
+}
@@ -0,0 +1,158 @@
+package switchbot
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock runs timers when advanced rather than when real time passes.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	at      time.Time
+	f       func()
+	stopped bool
+}
+
+func (k *fakeClock) Now() time.Time {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.now
+}
+
+func (k *fakeClock) AfterFunc(d time.Duration, f func()) func() bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	t := &fakeTimer{at: k.now.Add(d), f: f}
+	k.timers = append(k.timers, t)
+	return func() bool {
+		k.mu.Lock()
+		defer k.mu.Unlock()
+		was := !t.stopped
+		t.stopped = true
+		return was
+	}
+}
+
+// Advance moves the clock forward by d and runs the timers that are due, in
+// order, waiting for each to return.
+func (k *fakeClock) Advance(d time.Duration) {
+	k.mu.Lock()
+	k.now = k.now.Add(d)
+	var due []*fakeTimer
+	for _, t := range k.timers {
+		if !t.stopped && !t.at.After(k.now) {
+			t.stopped = true
+			due = append(due, t)
+		}
+	}
+	k.mu.Unlock()
+
+	for _, t := range due {
+		t.f()
+	}
+}
+
+func newScheduleClient(t *testing.T) (*Client, *commandServer, *fakeClock) {
+	t.Helper()
+	clock := &fakeClock{now: time.Date(2024, 6, 1, 22, 0, 0, 0, time.UTC)}
+	c, srv := newCommandClient(t, withSchedulerClock(clock.Now, clock.AfterFunc))
+	return c, srv, clock
+}
+
+func TestScheduleCommandFires(t *testing.T) {
+	c, srv, clock := newScheduleClient(t)
+	ctx := context.Background()
+	start := clock.Now()
+
+	late := c.ScheduleCommand(ctx, start.Add(30*time.Minute), "PLUG01", Command{Command: "turnOff"})
+	soon := c.ScheduleCommand(ctx, start.Add(10*time.Minute), "BOT01", Command{Command: "press"})
+
+	pending := c.PendingSchedules()
+	if len(pending) != 2 || pending[0].ID != soon.ID || pending[1].ID != late.ID {
+		t.Fatalf("PendingSchedules = %+v, want soonest first", pending)
+	}
+
+	clock.Advance(9 * time.Minute)
+	wantCommands(t, srv.commands())
+
+	clock.Advance(time.Minute)
+	wantCommands(t, srv.commands(), sentCommand{"BOT01", Command{"press", DefaultParameter, CommandTypeCommand}})
+	if pending := c.PendingSchedules(); len(pending) != 1 || pending[0].ID != late.ID {
+		t.Errorf("PendingSchedules after the first fired = %+v", pending)
+	}
+
+	clock.Advance(time.Hour)
+	wantCommands(t, srv.commands(),
+		sentCommand{"BOT01", Command{"press", DefaultParameter, CommandTypeCommand}},
+		sentCommand{"PLUG01", Command{"turnOff", DefaultParameter, CommandTypeCommand}},
+	)
+	if pending := c.PendingSchedules(); len(pending) != 0 {
+		t.Errorf("PendingSchedules = %+v, want none", pending)
+	}
+	if c.CancelSchedule(soon.ID) {
+		t.Error("CancelSchedule reported true for a command already sent")
+	}
+}
+
+func TestScheduleCommandCancel(t *testing.T) {
+	c, srv, clock := newScheduleClient(t)
+	s := c.ScheduleCommand(context.Background(), clock.Now().Add(time.Minute), "PLUG01", Command{Command: "turnOff"})
+
+	if !c.CancelSchedule(s.ID) {
+		t.Fatal("CancelSchedule reported false for a pending command")
+	}
+	if c.CancelSchedule(s.ID) {
+		t.Error("second CancelSchedule reported true")
+	}
+	clock.Advance(time.Hour)
+	wantCommands(t, srv.commands())
+	if pending := c.PendingSchedules(); len(pending) != 0 {
+		t.Errorf("PendingSchedules = %+v, want none", pending)
+	}
+}
+
+func TestScheduleCommandContextCancel(t *testing.T) {
+	c, srv, clock := newScheduleClient(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	c.ScheduleCommand(ctx, clock.Now().Add(time.Minute), "PLUG01", Command{Command: "turnOff"})
+	cancel()
+
+	// The context's AfterFunc cancels the schedule from its own goroutine
+	deadline := time.Now().Add(time.Second)
+	for len(c.PendingSchedules()) > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("schedule still pending after its context was cancelled")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	clock.Advance(time.Hour)
+	wantCommands(t, srv.commands())
+}
+
+func TestScheduleCommandInPast(t *testing.T) {
+	c, srv, clock := newScheduleClient(t)
+	c.ScheduleCommand(context.Background(), clock.Now().Add(-time.Minute), "BOT01", Command{Command: "press"})
+	clock.Advance(0)
+	wantCommands(t, srv.commands(), sentCommand{"BOT01", Command{"press", DefaultParameter, CommandTypeCommand}})
+}
+
+func TestScheduleCommandRealClock(t *testing.T) {
+	c, srv := newCommandClient(t)
+	c.ScheduleCommand(context.Background(), time.Now().Add(10*time.Millisecond), "BOT01", Command{Command: "press"})
+
+	deadline := time.Now().Add(time.Second)
+	for len(srv.commands()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("scheduled command not sent")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	wantCommands(t, srv.commands(), sentCommand{"BOT01", Command{"press", DefaultParameter, CommandTypeCommand}})
+}
@@ -0,0 +1,65 @@
+package switchbot
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// ScopedClient is a Client bound to a context, for request-scoped use in
+// servers: its methods are the Client's general-purpose methods without the
+// ctx argument. It shares the Client's caches, rate limiter and options, and
+// is as cheap to create as a struct. Device-specific helpers are reached
+// through Client.
+type ScopedClient struct {
+	c   *Client
+	ctx context.Context
+}
+
+// WithContext returns a ScopedClient whose calls use ctx, so cancelling ctx
+// cancels them.
+func (c *Client) WithContext(ctx context.Context) *ScopedClient {
+	return &ScopedClient{c: c, ctx: ctx}
+}
+
+// Client returns the underlying Client.
+func (s *ScopedClient) Client() *Client { return s.c }
+
+// Context returns the bound context.
+func (s *ScopedClient) Context() context.Context { return s.ctx }
+
+// Devices is Client.Devices with the bound context.
+func (s *ScopedClient) Devices() ([]Device, error) { return s.c.Devices(s.ctx) }
+
+// InfraredRemotes is Client.InfraredRemotes with the bound context.
+func (s *ScopedClient) InfraredRemotes() ([]InfraredRemote, error) {
+	return s.c.InfraredRemotes(s.ctx)
+}
+
+// ResolveID is Client.ResolveID with the bound context.
+func (s *ScopedClient) ResolveID(nameOrID string) (string, error) {
+	return s.c.ResolveID(s.ctx, nameOrID)
+}
+
+// DeviceStatus is Client.DeviceStatus with the bound context.
+func (s *ScopedClient) DeviceStatus(id string) (any, error) { return s.c.DeviceStatus(s.ctx, id) }
+
+// DeviceStatusRaw is Client.DeviceStatusRaw with the bound context.
+func (s *ScopedClient) DeviceStatusRaw(id string) (json.RawMessage, error) {
+	return s.c.DeviceStatusRaw(s.ctx, id)
+}
+
+// SendCommand is Client.SendCommand with the bound context.
+func (s *ScopedClient) SendCommand(id string, cmd Command, opts ...CallOption) error {
+	return s.c.SendCommand(s.ctx, id, cmd, opts...)
+}
+
+// Toggle is Client.Toggle with the bound context.
+func (s *ScopedClient) Toggle(id string) error { return s.c.Toggle(s.ctx, id) }
+
+// Scenes is Client.Scenes with the bound context.
+func (s *ScopedClient) Scenes() ([]Scene, error) { return s.c.Scenes(s.ctx) }
+
+// ExecuteScene is Client.ExecuteScene with the bound context.
+func (s *ScopedClient) ExecuteScene(id string) (*SceneResult, error) {
+	return s.c.ExecuteScene(s.ctx, id)
+}
@@ -0,0 +1,60 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestScopedClientCancellation(t *testing.T) {
+	c := newTestClient(t, slowHandler(time.Second))
+	ctx, cancel := context.WithCancel(context.Background())
+	s := c.WithContext(ctx)
+
+	errc := make(chan error, 1)
+	go func() {
+		_, err := s.DeviceStatusRaw("DEV000000001")
+		errc <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-errc:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("err = %v, want context.Canceled", err)
+		}
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("cancelling the bound context didn't cancel the call")
+	}
+
+	// Calls made after cancellation fail straight away
+	if err := s.SendCommand("DEV000000001", Command{Command: "press"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("after cancel: err = %v, want context.Canceled", err)
+	}
+}
+
+func TestScopedClientSharesClient(t *testing.T) {
+	var calls int
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		writeEnvelope(w, statusSuccess, deviceList{DeviceList: []Device{{DeviceID: "DEV000000001", DeviceName: "Living Room Bot"}}})
+	}, WithDeviceCache(time.Hour))
+	ctx := context.Background()
+
+	if _, err := c.Devices(ctx); err != nil {
+		t.Fatal(err)
+	}
+	s := c.WithContext(ctx)
+	if s.Client() != c || s.Context() != ctx {
+		t.Error("scoped client doesn't expose its client and context")
+	}
+	if _, err := s.Devices(); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Errorf("device list fetched %d times, want the cache shared", calls)
+	}
+}
@@ -0,0 +1,132 @@
+package switchbot
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestCorrelationIDInNonceAndLog(t *testing.T) {
+	var nonces []string
+	h := func(w http.ResponseWriter, r *http.Request) {
+		nonces = append(nonces, r.Header.Get("nonce"))
+		success(w, r)
+	}
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	c := newTestClient(t, h, WithLogger(logger))
+
+	ctx := WithCorrelationID(context.Background(), "order 42/7")
+	for i := 0; i < 2; i++ {
+		if err := c.do(ctx, http.MethodGet, "/devices", nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(nonces) != 2 || nonces[0] == nonces[1] {
+		t.Fatalf("nonces = %q, want two distinct", nonces)
+	}
+	for _, n := range nonces {
+		if !strings.HasPrefix(n, "order427-") || len(n) <= len("order427-") {
+			t.Errorf("nonce %q doesn't combine the sanitized id with a unique part", n)
+		}
+	}
+
+	var line struct {
+		Nonce         string `json:"nonce"`
+		CorrelationID string `json:"correlation_id"`
+	}
+	first, _, _ := strings.Cut(logs.String(), "\n")
+	if err := json.Unmarshal([]byte(first), &line); err != nil {
+		t.Fatalf("log line %q: %v", first, err)
+	}
+	if line.CorrelationID != "order 42/7" || line.Nonce != nonces[0] {
+		t.Errorf("log line = %+v, want the correlation id and nonce %q", line, nonces[0])
+	}
+}
+
+func TestNonceWithoutCorrelationID(t *testing.T) {
+	if n := newNonce(randomUUID{}, ""); strings.Contains(n, "--") || len(n) != 36 {
+		t.Errorf("nonce without correlation id = %q, want a bare UUID", n)
+	}
+	long := strings.Repeat("x", 3*maxCorrelationLen)
+	if n := newNonce(randomUUID{}, long); len(n) != maxCorrelationLen+1+36 {
+		t.Errorf("nonce length %d, want the id truncated to %d", len(n), maxCorrelationLen)
+	}
+}
+
+var uuidV4 = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestRandomUUID(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		n := randomUUID{}.Nonce()
+		if !uuidV4.MatchString(n) {
+			t.Fatalf("nonce %q isn't a version 4 UUID", n)
+		}
+		if seen[n] {
+			t.Fatalf("nonce %q generated twice", n)
+		}
+		seen[n] = true
+	}
+}
+
+func TestWithNonceGenerator(t *testing.T) {
+	var got string
+	h := func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("nonce")
+		success(w, r)
+	}
+	c := newTestClient(t, h, WithNonceGenerator(NonceFunc(func() string { return "fixed-nonce" })))
+	if err := c.do(context.Background(), http.MethodGet, "/devices", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got != "fixed-nonce" {
+		t.Errorf("nonce = %q, want the generator's value", got)
+	}
+
+	if c := newTestClient(t, h, WithNonceGenerator(nil)); c.nonces == nil {
+		t.Error("WithNonceGenerator(nil) removed the default generator")
+	}
+}
+
+func TestSignedHeaders(t *testing.T) {
+	c, err := NewClient(testToken, testSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := c.SignedHeaders()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"Authorization", "Content-Type", "charset", "t", "sign", "nonce"} {
+		if first[key] == "" {
+			t.Errorf("header %s missing", key)
+		}
+	}
+	if first["Authorization"] != testToken {
+		t.Errorf("Authorization = %q, want the token", first["Authorization"])
+	}
+
+	mac := hmac.New(sha256.New, []byte(testSecret))
+	mac.Write([]byte(testToken + first["t"] + first["nonce"]))
+	if want := base64.StdEncoding.EncodeToString(mac.Sum(nil)); first["sign"] != want {
+		t.Errorf("sign = %q, want %q", first["sign"], want)
+	}
+
+	second, err := c.SignedHeaders()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second["nonce"] == first["nonce"] || second["sign"] == first["sign"] {
+		t.Error("two calls returned the same nonce or signature")
+	}
+}
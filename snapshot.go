@@ -0,0 +1,88 @@
+package switchbot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+)
+
+// snapshotError is the marker SnapshotAll records for a device whose status
+// could not be read.
+type snapshotError struct {
+	Error    string `json:"error"`
+	TimedOut bool   `json:"timedOut,omitempty"`
+}
+
+func snapshotErrorBody(err error) json.RawMessage {
+	raw, _ := json.Marshal(snapshotError{
+		Error:    err.Error(),
+		TimedOut: errors.Is(err, context.DeadlineExceeded),
+	})
+	return raw
+}
+
+// SnapshotAll reads the status of every physical device on the account and
+// returns the raw status bodies keyed by deviceId.
+//
+// Reads run concurrently, bounded by the client's concurrency and rate limit.
+// A device whose status can't be read (e.g. because it is offline) is still
+// present in the result, with a body of the form {"error":"..."}. Only a
+// failure to list the devices is returned as an error.
+//
+// ctx's deadline applies to the snapshot as a whole: once it passes,
+// SnapshotAll returns straight away, cancelling reads still in flight and
+// recording unfinished devices as {"error":"...","timedOut":true}.
+func (c *Client) SnapshotAll(ctx context.Context) (map[string]json.RawMessage, error) {
+	devices, err := c.Devices(ctx)
+	if errors.Is(err, ErrNoDevices) {
+		return map[string]json.RawMessage{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	finished := false
+	snapshot := make(map[string]json.RawMessage, len(devices))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.forEach(len(devices), func(i int) {
+			if ctx.Err() != nil {
+				return
+			}
+			id := devices[i].DeviceID
+			raw, _, err := c.deviceStatusRaw(ctx, id)
+			if err != nil {
+				raw = snapshotErrorBody(err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			// Reads finishing after the deadline are dropped; their
+			// devices are already marked as timed out
+			if !finished {
+				snapshot[id] = raw
+			}
+		})
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	finished = true
+	for _, d := range devices {
+		if _, ok := snapshot[d.DeviceID]; !ok {
+			snapshot[d.DeviceID] = snapshotErrorBody(context.Cause(ctx))
+		}
+	}
+	return snapshot, nil
+}
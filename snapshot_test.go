@@ -0,0 +1,63 @@
+package switchbot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSnapshotAllSharedDeadline(t *testing.T) {
+	devices := []Device{
+		{DeviceID: "FAST", DeviceType: DeviceTypeBot},
+		{DeviceID: "SLOW", DeviceType: DeviceTypeBot},
+		{DeviceID: "OFFLINE", DeviceType: DeviceTypeBot},
+	}
+	release := make(chan struct{})
+	defer close(release)
+	h := func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case apiVersion + "/devices":
+			writeEnvelope(w, statusSuccess, deviceList{DeviceList: devices})
+		case apiVersion + "/devices/SLOW/status":
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		case apiVersion + "/devices/OFFLINE/status":
+			writeAPIError(w, 161, "device offline")
+		default:
+			id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, apiVersion+"/devices/"), "/status")
+			writeEnvelope(w, statusSuccess, map[string]any{"deviceId": id, "deviceType": "Bot", "power": "on"})
+		}
+	}
+	c := newTestClient(t, h)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	snapshot, err := c.SnapshotAll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("SnapshotAll took %v, want it bounded by the 200ms deadline", elapsed)
+	}
+	if len(snapshot) != len(devices) {
+		t.Fatalf("snapshot has %d devices, want %d", len(snapshot), len(devices))
+	}
+
+	var fast struct{ Power string }
+	if err := json.Unmarshal(snapshot["FAST"], &fast); err != nil || fast.Power != "on" {
+		t.Errorf("FAST = %s, want its status", snapshot["FAST"])
+	}
+	var slow, offline snapshotError
+	if err := json.Unmarshal(snapshot["SLOW"], &slow); err != nil || !slow.TimedOut {
+		t.Errorf("SLOW = %s, want marked as timed out", snapshot["SLOW"])
+	}
+	if err := json.Unmarshal(snapshot["OFFLINE"], &offline); err != nil || offline.Error == "" || offline.TimedOut {
+		t.Errorf("OFFLINE = %s, want an error that isn't a timeout", snapshot["OFFLINE"])
+	}
+}
@@ -0,0 +1,286 @@
+package switchbot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// DeviceType is the deviceType string SwitchBot reports for a device.
+type DeviceType string
+
+// Device types as reported in the device list and status bodies.
+const (
+	DeviceTypeAirPurifierVOC       DeviceType = "Air Purifier VOC"
+	DeviceTypeAirPurifierTableVOC  DeviceType = "Air Purifier Table VOC"
+	DeviceTypeAirPurifierPM25      DeviceType = "Air Purifier PM2.5"
+	DeviceTypeAirPurifierTablePM25 DeviceType = "Air Purifier Table PM2.5"
+	DeviceTypeBot                  DeviceType = "Bot"
+	DeviceTypeColorBulb            DeviceType = "Color Bulb"
+	DeviceTypeStripLight           DeviceType = "Strip Light"
+	DeviceTypeStripLight3          DeviceType = "Strip Light 3"
+	DeviceTypeFloorLamp            DeviceType = "Floor Lamp"
+	DeviceTypeCeilingLight         DeviceType = "Ceiling Light"
+	DeviceTypeCeilingLightPro      DeviceType = "Ceiling Light Pro"
+	DeviceTypeCirculatorFan        DeviceType = "Battery Circulator Fan"
+	DeviceTypeContactSensor        DeviceType = "Contact Sensor"
+	DeviceTypeHubMini              DeviceType = "Hub Mini"
+	DeviceTypeHubPlus              DeviceType = "Hub Plus"
+	DeviceTypeHub2                 DeviceType = "Hub 2"
+	DeviceTypeHumidifier           DeviceType = "Humidifier"
+	DeviceTypeKeypad               DeviceType = "Keypad"
+	DeviceTypeKeypadTouch          DeviceType = "Keypad Touch"
+	DeviceTypeMeter                DeviceType = "Meter"
+	DeviceTypeMeterPlus            DeviceType = "MeterPlus"
+	DeviceTypeMeterPro             DeviceType = "MeterPro"
+	DeviceTypeMeterProCO2          DeviceType = "MeterPro(CO2)"
+	DeviceTypeOutdoorMeter         DeviceType = "WoIOSensor"
+	DeviceTypeMotionSensor         DeviceType = "Motion Sensor"
+	DeviceTypeBlindTilt            DeviceType = "Blind Tilt"
+	DeviceTypeCurtain              DeviceType = "Curtain"
+	DeviceTypeCurtain3             DeviceType = "Curtain3"
+	DeviceTypeLock                 DeviceType = "Smart Lock"
+	DeviceTypeLockPro              DeviceType = "Smart Lock Pro"
+	DeviceTypePlug                 DeviceType = "Plug"
+	DeviceTypePlugMiniUS           DeviceType = "Plug Mini (US)"
+	DeviceTypePlugMiniJP           DeviceType = "Plug Mini (JP)"
+	DeviceTypeVacuumS1             DeviceType = "Robot Vacuum Cleaner S1"
+	DeviceTypeVacuumS1Plus         DeviceType = "Robot Vacuum Cleaner S1 Plus"
+	DeviceTypeVacuumK10Plus        DeviceType = "K10+"
+	DeviceTypeVacuumK10PlusPro     DeviceType = "K10+ Pro"
+	DeviceTypeVacuumS10            DeviceType = "Robot Vacuum Cleaner S10"
+	DeviceTypeWaterLeakDetector    DeviceType = "Water Detector"
+
+	// DeviceTypeInfraredRemote is not a SwitchBot type: this package sets it
+	// on IR remotes listed alongside devices, e.g. by ControllableDevices.
+	DeviceTypeInfraredRemote DeviceType = "Infrared Remote"
+)
+
+// PowerState is the "on"/"off" power field reported by switchable devices.
+type PowerState string
+
+const (
+	PowerOn  PowerState = "on"
+	PowerOff PowerState = "off"
+)
+
+// BaseStatus holds the fields every status body carries.
+type BaseStatus struct {
+	DeviceID    string     `json:"deviceId"`
+	DeviceType  DeviceType `json:"deviceType"`
+	HubDeviceID string     `json:"hubDeviceId"`
+}
+
+// UnknownStatus is returned by DeviceStatus for device types without a typed
+// status. Raw holds the undecoded status body.
+type UnknownStatus struct {
+	BaseStatus
+	Raw json.RawMessage `json:"-"`
+}
+
+// MarshalJSON wraps the raw status body, so no fields are lost, in an object
+// with the "type" field UnmarshalStatus uses to restore the status. The body
+// is nested under "raw" rather than merged, so a field of its own can't
+// clash with "type".
+func (s UnknownStatus) MarshalJSON() ([]byte, error) {
+	raw := s.Raw
+	if raw == nil {
+		b, err := json.Marshal(s.BaseStatus)
+		if err != nil {
+			return nil, err
+		}
+		raw = b
+	}
+	return marshalStatus(statusKindUnknown, unknownStatusJSON{Raw: raw})
+}
+
+// unknownStatusJSON is the stored form of an UnknownStatus, less its "type".
+type unknownStatusJSON struct {
+	Raw json.RawMessage `json:"raw"`
+}
+
+// statusTypes maps a device type to a constructor for its typed status.
+var statusTypes = map[DeviceType]func() any{
+	DeviceTypeAirPurifierVOC:       func() any { return new(AirPurifierStatus) },
+	DeviceTypeAirPurifierTableVOC:  func() any { return new(AirPurifierStatus) },
+	DeviceTypeAirPurifierPM25:      func() any { return new(AirPurifierStatus) },
+	DeviceTypeAirPurifierTablePM25: func() any { return new(AirPurifierStatus) },
+	DeviceTypeBlindTilt:            func() any { return new(BlindTiltStatus) },
+	DeviceTypeBot:                  func() any { return new(BotStatus) },
+	DeviceTypeCeilingLight:         func() any { return new(LightStatus) },
+	DeviceTypeCeilingLightPro:      func() any { return new(LightStatus) },
+	DeviceTypeCirculatorFan:        func() any { return new(FanStatus) },
+	DeviceTypeColorBulb:            func() any { return new(LightStatus) },
+	DeviceTypeContactSensor:        func() any { return new(ContactStatus) },
+	DeviceTypeCurtain:              func() any { return new(CurtainStatus) },
+	DeviceTypeCurtain3:             func() any { return new(CurtainStatus) },
+	DeviceTypeFloorLamp:            func() any { return new(LightStatus) },
+	DeviceTypeHub2:                 func() any { return new(HubStatus) },
+	DeviceTypeHubMini:              func() any { return new(HubStatus) },
+	DeviceTypeHubPlus:              func() any { return new(HubStatus) },
+	DeviceTypeHumidifier:           func() any { return new(HumidifierStatus) },
+	DeviceTypeKeypad:               func() any { return new(KeypadStatus) },
+	DeviceTypeKeypadTouch:          func() any { return new(KeypadStatus) },
+	DeviceTypeLock:                 func() any { return new(LockStatus) },
+	DeviceTypeLockPro:              func() any { return new(LockStatus) },
+	DeviceTypeMeter:                func() any { return new(MeterStatus) },
+	DeviceTypeMeterPlus:            func() any { return new(MeterStatus) },
+	DeviceTypeMeterPro:             func() any { return new(MeterStatus) },
+	DeviceTypeMeterProCO2:          func() any { return new(MeterStatus) },
+	DeviceTypeMotionSensor:         func() any { return new(MotionStatus) },
+	DeviceTypeOutdoorMeter:         func() any { return new(MeterStatus) },
+	DeviceTypePlug:                 func() any { return new(PlugStatus) },
+	DeviceTypePlugMiniUS:           func() any { return new(PlugStatus) },
+	DeviceTypePlugMiniJP:           func() any { return new(PlugStatus) },
+	DeviceTypeStripLight:           func() any { return new(LightStatus) },
+	DeviceTypeStripLight3:          func() any { return new(LightStatus) },
+	DeviceTypeVacuumK10Plus:        func() any { return new(VacuumStatus) },
+	DeviceTypeVacuumK10PlusPro:     func() any { return new(VacuumStatus) },
+	DeviceTypeVacuumS1:             func() any { return new(VacuumStatus) },
+	DeviceTypeVacuumS1Plus:         func() any { return new(VacuumStatus) },
+	DeviceTypeVacuumS10:            func() any { return new(VacuumStatus) },
+	DeviceTypeWaterLeakDetector:    func() any { return new(WaterLeakStatus) },
+}
+
+// DeviceStatus fetches the status of a device and decodes it into the typed
+// struct for its device type, e.g. *BlindTiltStatus. Device types the package
+// doesn't model are returned as *UnknownStatus. It is DeviceStatusRaw followed
+// by decoding.
+func (c *Client) DeviceStatus(ctx context.Context, id string) (any, error) {
+	raw, err := c.DeviceStatusRaw(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return c.decodeStatus(raw)
+}
+
+// DeviceStatusRaw fetches the status body of a device without decoding it,
+// after the envelope has been checked. It is the escape hatch for fields the
+// typed statuses don't model yet.
+func (c *Client) DeviceStatusRaw(ctx context.Context, id string) (json.RawMessage, error) {
+	raw, _, err := c.deviceStatusRaw(ctx, id)
+	return raw, err
+}
+
+// ErrStatusUnsupported is returned by the status methods for devices that
+// report no status, such as IR remotes. See SupportsStatus.
+var ErrStatusUnsupported = errors.New("device does not report status")
+
+// statusUnsupported is the envelope statusCode for a status read on a device
+// type without status.
+const statusUnsupported = 151
+
+// deviceStatusRaw fetches the undecoded status body of a device. With
+// WithStatusETags it also reports whether the body was served from the ETag
+// cache after a 304.
+//
+// Devices the cached device list shows can't report status fail with
+// ErrStatusUnsupported without a request; otherwise the API's statusCode 151
+// is turned into the same error.
+func (c *Client) deviceStatusRaw(ctx context.Context, id string) (json.RawMessage, bool, error) {
+	if t := c.cachedTypeOf(id); t != "" && !SupportsStatus(t) {
+		return nil, false, fmt.Errorf("%w: %s (%s)", ErrStatusUnsupported, id, t)
+	}
+
+	var raw json.RawMessage
+	r := &apiRequest{
+		method: http.MethodGet,
+		path:   "/devices/" + url.PathEscape(id) + "/status",
+		out:    &raw,
+	}
+
+	cached, hasCached := c.etags.get(id)
+	if hasCached {
+		r.header = http.Header{"If-None-Match": {cached.etag}}
+	}
+
+	if err := c.send(ctx, r); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == statusUnsupported {
+			return nil, false, fmt.Errorf("%w: %s: %w", ErrStatusUnsupported, id, err)
+		}
+		return nil, false, err
+	}
+	if r.notModified && hasCached {
+		return cached.raw, true, nil
+	}
+
+	c.etags.put(id, r.respHeader.Get("ETag"), raw)
+	return raw, false, nil
+}
+
+// ConditionalStatus is a status read with WithStatusETags. NotModified is
+// set when SwitchBot answered 304 and Status is the remembered status.
+type ConditionalStatus struct {
+	Status      any
+	NotModified bool
+}
+
+// DeviceStatusConditional is DeviceStatus, additionally reporting whether a
+// conditional request found the status unchanged. Without WithStatusETags
+// NotModified is always false.
+func (c *Client) DeviceStatusConditional(ctx context.Context, id string) (*ConditionalStatus, error) {
+	raw, notModified, err := c.deviceStatusRaw(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	status, err := c.decodeStatus(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &ConditionalStatus{Status: status, NotModified: notModified}, nil
+}
+
+// decodeStatus picks the typed status for the deviceType in raw and decodes
+// into it.
+func (c *Client) decodeStatus(raw json.RawMessage) (any, error) {
+	var base BaseStatus
+	if err := json.Unmarshal(raw, &base); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDecode, err)
+	}
+
+	newStatus, ok := statusTypes[base.DeviceType]
+	if !ok {
+		return &UnknownStatus{BaseStatus: base, Raw: raw}, nil
+	}
+
+	status := newStatus()
+	if err := c.unmarshal(raw, status); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrDecode, base.DeviceType, err)
+	}
+	return status, nil
+}
+
+// DeviceStatusInto fetches the status of a device and decodes it into v,
+// which should be a pointer to a struct of the caller's own, e.g. one with
+// only the fields it needs. Unknown fields are ignored even with
+// WithStrictDecoding. A body that doesn't decode into v is reported as
+// ErrDecode.
+func (c *Client) DeviceStatusInto(ctx context.Context, id string, v any) error {
+	raw, err := c.DeviceStatusRaw(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("%w: %s into %T: %w", ErrDecode, id, v, err)
+	}
+	return nil
+}
+
+// typedStatus fetches the status of id and returns it as *T, failing with
+// ErrUnexpectedDeviceType if the device decodes to a different status type.
+func typedStatus[T any](ctx context.Context, c *Client, id string) (*T, error) {
+	status, err := c.DeviceStatus(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	typed, ok := status.(*T)
+	if !ok {
+		return nil, fmt.Errorf("%w: device %s has status %T, want %T", ErrUnexpectedDeviceType, id, status, typed)
+	}
+	return typed, nil
+}
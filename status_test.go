@@ -0,0 +1,163 @@
+package switchbot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// statusServer answers every status read with body.
+func statusServer(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeEnvelope(w, statusSuccess, json.RawMessage(body))
+	}
+}
+
+func TestDeviceStatusRawKeepsUnmodeledFields(t *testing.T) {
+	body := `{"deviceId":"DEV000000003","deviceType":"Plug Mini (US)","hubDeviceId":"","power":"on","voltage":120.1,"electricCurrent":130,"newFirmwareField":7}`
+	c := newTestClient(t, statusServer(body))
+	ctx := context.Background()
+
+	raw, err := c.DeviceStatusRaw(ctx, "DEV000000003")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if fields["newFirmwareField"] != 7.0 {
+		t.Errorf("raw status lost newFirmwareField: %s", raw)
+	}
+
+	status, err := c.DeviceStatus(ctx, "DEV000000003")
+	if err != nil {
+		t.Fatal(err)
+	}
+	plug, ok := status.(*PlugStatus)
+	if !ok {
+		t.Fatalf("DeviceStatus returned %T, want *PlugStatus", status)
+	}
+	if plug.Power != PowerOn || plug.Voltage != 120.1 || plug.ElectricCurrent != 130 {
+		t.Errorf("plug status = %+v", plug)
+	}
+}
+
+func TestDeviceStatusUnknownType(t *testing.T) {
+	body := `{"deviceId":"DEV000000099","deviceType":"Future Gadget","hubDeviceId":"","level":3}`
+	c := newTestClient(t, statusServer(body))
+
+	status, err := c.DeviceStatus(context.Background(), "DEV000000099")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unknown, ok := status.(*UnknownStatus)
+	if !ok {
+		t.Fatalf("DeviceStatus returned %T, want *UnknownStatus", status)
+	}
+	if unknown.DeviceType != "Future Gadget" || string(unknown.Raw) != body {
+		t.Errorf("unknown status = %+v, raw %s", unknown.BaseStatus, unknown.Raw)
+	}
+}
+
+func TestStrictDecodingRejectsUnknownFields(t *testing.T) {
+	body := `{"deviceId":"DEV000000001","deviceType":"Bot","hubDeviceId":"HUB000000001","power":"on","battery":90,"deviceMode":"switchMode","newFirmwareField":7}`
+
+	strict := newTestClient(t, statusServer(body), WithStrictDecoding(true))
+	if _, err := strict.BotStatus(context.Background(), "DEV000000001"); !errors.Is(err, ErrDecode) {
+		t.Errorf("strict: err = %v, want ErrDecode", err)
+	}
+
+	lenient := newTestClient(t, statusServer(body))
+	if _, err := lenient.BotStatus(context.Background(), "DEV000000001"); err != nil {
+		t.Errorf("lenient: %v", err)
+	}
+}
+
+func TestDeviceStatusInto(t *testing.T) {
+	body := `{"deviceId":"DEV000000001","deviceType":"Meter","hubDeviceId":"HUB000000001","temperature":21.5,"humidity":48,"battery":100}`
+	c := newTestClient(t, statusServer(body), WithStrictDecoding(true))
+
+	var partial struct {
+		Temperature float64 `json:"temperature"`
+		Humidity    int     `json:"humidity"`
+	}
+	if err := c.DeviceStatusInto(context.Background(), "DEV000000001", &partial); err != nil {
+		t.Fatal(err)
+	}
+	if partial.Temperature != 21.5 || partial.Humidity != 48 {
+		t.Errorf("decoded %+v", partial)
+	}
+
+	var wrong struct {
+		Temperature string `json:"temperature"`
+	}
+	if err := c.DeviceStatusInto(context.Background(), "DEV000000001", &wrong); !errors.Is(err, ErrDecode) {
+		t.Errorf("mismatched field type: err = %v, want ErrDecode", err)
+	}
+}
+
+func TestDeviceStatusIntoAPIError(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeAPIError(w, statusDeviceNotFound, "device not found")
+	})
+	var v struct{}
+	if err := c.DeviceStatusInto(context.Background(), "DEV000000001", &v); !errors.Is(err, ErrDeviceNotFound) {
+		t.Errorf("err = %v, want ErrDeviceNotFound", err)
+	}
+}
+
+func TestSupportsStatus(t *testing.T) {
+	for typ, want := range map[DeviceType]bool{
+		DeviceTypeInfraredRemote: false,
+		"":                       false,
+		DeviceTypeMeter:          true,
+		DeviceTypeBot:            true,
+		"Future Gadget":          true,
+	} {
+		if got := SupportsStatus(typ); got != want {
+			t.Errorf("SupportsStatus(%q) = %v, want %v", typ, got, want)
+		}
+	}
+}
+
+func TestDeviceStatusIRRemoteFromCache(t *testing.T) {
+	var statusReads int
+	remotes := []InfraredRemote{{DeviceID: "IR01", DeviceName: "TV", RemoteType: "TV"}}
+	h := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == apiVersion+"/devices" {
+			writeEnvelope(w, statusSuccess, deviceList{InfraredRemoteList: remotes})
+			return
+		}
+		statusReads++
+		writeAPIError(w, statusUnsupported, "unsupported")
+	}
+	c := newTestClient(t, h, WithDeviceCache(time.Hour))
+	if _, err := c.Devices(context.Background()); err != nil && !errors.Is(err, ErrNoDevices) {
+		t.Fatal(err)
+	}
+
+	if _, err := c.DeviceStatus(context.Background(), "IR01"); !errors.Is(err, ErrStatusUnsupported) {
+		t.Errorf("err = %v, want ErrStatusUnsupported", err)
+	}
+	if statusReads != 0 {
+		t.Errorf("%d status reads, want none for a remote known from the device list", statusReads)
+	}
+}
+
+func TestDeviceStatusUnsupportedFromAPI(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeAPIError(w, statusUnsupported, "unsupported")
+	})
+	_, err := c.DeviceStatus(context.Background(), "IR01")
+	if !errors.Is(err, ErrStatusUnsupported) {
+		t.Fatalf("err = %v, want ErrStatusUnsupported", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != statusUnsupported {
+		t.Errorf("err = %v, want the API error kept", err)
+	}
+}
@@ -0,0 +1,104 @@
+package switchbot
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Status kinds written to the "type" field by the MarshalJSON methods of the
+// status types. They are part of the stored format and must not change.
+const (
+	statusKindAirPurifier = "airPurifier"
+	statusKindBlindTilt   = "blindTilt"
+	statusKindBot         = "bot"
+	statusKindContact     = "contact"
+	statusKindCurtain     = "curtain"
+	statusKindFan         = "fan"
+	statusKindHub         = "hub"
+	statusKindHumidifier  = "humidifier"
+	statusKindKeypad      = "keypad"
+	statusKindLight       = "light"
+	statusKindLock        = "lock"
+	statusKindMeter       = "meter"
+	statusKindMotion      = "motion"
+	statusKindPlug        = "plug"
+	statusKindUnknown     = "unknown"
+	statusKindVacuum      = "vacuum"
+	statusKindWaterLeak   = "waterLeak"
+)
+
+// statusKinds maps a "type" discriminator back to its status type.
+var statusKinds = map[string]func() any{
+	statusKindAirPurifier: func() any { return new(AirPurifierStatus) },
+	statusKindBlindTilt:   func() any { return new(BlindTiltStatus) },
+	statusKindBot:         func() any { return new(BotStatus) },
+	statusKindContact:     func() any { return new(ContactStatus) },
+	statusKindCurtain:     func() any { return new(CurtainStatus) },
+	statusKindFan:         func() any { return new(FanStatus) },
+	statusKindHub:         func() any { return new(HubStatus) },
+	statusKindHumidifier:  func() any { return new(HumidifierStatus) },
+	statusKindKeypad:      func() any { return new(KeypadStatus) },
+	statusKindLight:       func() any { return new(LightStatus) },
+	statusKindLock:        func() any { return new(LockStatus) },
+	statusKindMeter:       func() any { return new(MeterStatus) },
+	statusKindMotion:      func() any { return new(MotionStatus) },
+	statusKindPlug:        func() any { return new(PlugStatus) },
+	statusKindVacuum:      func() any { return new(VacuumStatus) },
+	statusKindWaterLeak:   func() any { return new(WaterLeakStatus) },
+}
+
+// marshalStatus encodes v, which must marshal to a JSON object, with a
+// leading "type" field set to kind.
+func marshalStatus(kind string, v any) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	out := []byte(`{"type":`)
+	out = append(out, fmt.Sprintf("%q", kind)...)
+	if len(b) > 2 {
+		out = append(out, ',')
+	}
+	return append(out, b[1:]...), nil
+}
+
+// UnmarshalStatus decodes a status previously encoded with json.Marshal,
+// choosing the status type from its "type" field. It returns a pointer, e.g.
+// *PlugStatus, like DeviceStatus does; an UnknownStatus comes back with its
+// raw body.
+func UnmarshalStatus(b []byte) (any, error) {
+	var head struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(b, &head); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDecode, err)
+	}
+	if head.Type == statusKindUnknown {
+		return unmarshalUnknownStatus(b)
+	}
+
+	newStatus, ok := statusKinds[head.Type]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown type %q", ErrDecode, head.Type)
+	}
+
+	status := newStatus()
+	if err := json.Unmarshal(b, status); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrDecode, head.Type, err)
+	}
+	return status, nil
+}
+
+// unmarshalUnknownStatus restores an UnknownStatus from its stored form.
+func unmarshalUnknownStatus(b []byte) (*UnknownStatus, error) {
+	var stored unknownStatusJSON
+	if err := json.Unmarshal(b, &stored); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrDecode, statusKindUnknown, err)
+	}
+	status := &UnknownStatus{Raw: stored.Raw}
+	if err := json.Unmarshal(stored.Raw, &status.BaseStatus); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrDecode, statusKindUnknown, err)
+	}
+	return status, nil
+}
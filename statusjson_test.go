@@ -0,0 +1,92 @@
+package switchbot
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func intPtr(n int) *int { return &n }
+
+// roundTripStatuses has a status of every kind UnmarshalStatus knows.
+var roundTripStatuses = map[string]any{
+	statusKindAirPurifier: &AirPurifierStatus{BaseStatus: BaseStatus{"DEV000000020", DeviceTypeAirPurifierPM25, "HUB000000001"}, Power: "ON", Mode: 2, ChildLock: 1, PM25: intPtr(12)},
+	statusKindBlindTilt:   &BlindTiltStatus{BaseStatus: BaseStatus{"DEV000000005", DeviceTypeBlindTilt, "HUB000000001"}, Version: "V2.6", Calibrate: true, Direction: "up", SlidePosition: 50, Battery: 80},
+	statusKindBot:         &BotStatus{BaseStatus: BaseStatus{"DEV000000001", DeviceTypeBot, "HUB000000001"}, Version: "V6.6", Power: PowerOn, Battery: 95, DeviceMode: "switchMode"},
+	statusKindContact:     &ContactStatus{BaseStatus: BaseStatus{"DEV000000026", DeviceTypeContactSensor, "HUB000000001"}, Battery: 70, OpenState: ContactOpen, Brightness: BrightnessDim},
+	statusKindCurtain:     &CurtainStatus{BaseStatus: BaseStatus{"DEV000000002", DeviceTypeCurtain, "HUB000000001"}, Calibrate: true, Battery: 60, SlidePosition: 100},
+	statusKindFan:         &FanStatus{BaseStatus: BaseStatus{"DEV000000019", DeviceTypeCirculatorFan, ""}, Power: PowerOn, Mode: "direct", FanSpeed: 40, Oscillation: PowerOff},
+	statusKindHub:         &HubStatus{BaseStatus: BaseStatus{"HUB000000001", DeviceTypeHub2, ""}, Temperature: new(FlexFloat), Humidity: new(FlexInt), LightLevel: intPtr(10)},
+	statusKindHumidifier:  &HumidifierStatus{BaseStatus: BaseStatus{"DEV000000014", DeviceTypeHumidifier, ""}, Power: PowerOn, Humidity: 45, Temperature: 21.5, Auto: true, NebulizationEfficiency: intPtr(80)},
+	statusKindKeypad:      &KeypadStatus{BaseStatus: BaseStatus{"DEV000000017", DeviceTypeKeypad, "HUB000000001"}, Battery: 90},
+	statusKindLight:       &LightStatus{BaseStatus: BaseStatus{"DEV000000008", DeviceTypeColorBulb, ""}, Power: PowerOn, Brightness: 80, Color: "255:0:0", ColorTemperature: 4000},
+	statusKindLock:        &LockStatus{BaseStatus: BaseStatus{"DEV000000006", DeviceTypeLock, "HUB000000001"}, Battery: 85, Calibrate: true, LockState: "locked", DoorState: "closed"},
+	statusKindMeter:       &MeterStatus{BaseStatus: BaseStatus{"DEV000000004", DeviceTypeMeterProCO2, "HUB000000001"}, Temperature: 22.3, Humidity: 48, Battery: 100, CO2: intPtr(650)},
+	statusKindMotion:      &MotionStatus{BaseStatus: BaseStatus{"DEV000000025", DeviceTypeMotionSensor, "HUB000000001"}, Battery: 88, MoveDetected: true, Brightness: BrightnessBright},
+	statusKindPlug:        &PlugStatus{BaseStatus: BaseStatus{"DEV000000003", DeviceTypePlugMiniUS, ""}, Power: PowerOn, Voltage: 120.1, Weight: 15.5, ElectricityOfDay: 30, ElectricCurrent: 130},
+	statusKindVacuum:      &VacuumStatus{BaseStatus: BaseStatus{"DEV000000021", DeviceTypeVacuumS10, ""}, WorkingStatus: "Clearing", OnlineStatus: "online", Battery: 75},
+	statusKindWaterLeak:   &WaterLeakStatus{BaseStatus: BaseStatus{"DEV000000023", DeviceTypeWaterLeakDetector, "HUB000000001"}, Battery: 90, Status: WaterLeak},
+}
+
+func TestStatusRoundTrip(t *testing.T) {
+	for kind := range statusKinds {
+		if _, ok := roundTripStatuses[kind]; !ok {
+			t.Errorf("no round trip status for kind %q", kind)
+		}
+	}
+
+	for kind, status := range roundTripStatuses {
+		b, err := json.Marshal(status)
+		if err != nil {
+			t.Fatalf("%s: %v", kind, err)
+		}
+		if !strings.HasPrefix(string(b), `{"type":"`+kind+`",`) {
+			t.Errorf("%s: encoded as %s, want a leading type field", kind, b)
+		}
+
+		got, err := UnmarshalStatus(b)
+		if err != nil {
+			t.Fatalf("%s: UnmarshalStatus: %v", kind, err)
+		}
+		if !reflect.DeepEqual(got, status) {
+			t.Errorf("%s: round trip = %+v, want %+v", kind, got, status)
+		}
+	}
+}
+
+func TestUnknownStatusRoundTrip(t *testing.T) {
+	raw := json.RawMessage(`{"deviceId":"DEV000000099","deviceType":"Future Gadget","hubDeviceId":"HUB000000001","type":"gizmo","level":3}`)
+	status := &UnknownStatus{
+		BaseStatus: BaseStatus{"DEV000000099", "Future Gadget", "HUB000000001"},
+		Raw:        raw,
+	}
+
+	b, err := json.Marshal(status)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := UnmarshalStatus(b)
+	if err != nil {
+		t.Fatalf("UnmarshalStatus(%s): %v", b, err)
+	}
+	unknown, ok := got.(*UnknownStatus)
+	if !ok {
+		t.Fatalf("UnmarshalStatus returned %T, want *UnknownStatus", got)
+	}
+	if unknown.BaseStatus != status.BaseStatus {
+		t.Errorf("BaseStatus = %+v, want %+v", unknown.BaseStatus, status.BaseStatus)
+	}
+	if string(unknown.Raw) != string(raw) {
+		t.Errorf("Raw = %s, want %s", unknown.Raw, raw)
+	}
+}
+
+func TestUnmarshalStatusErrors(t *testing.T) {
+	for _, b := range []string{`not json`, `{"deviceId":"X"}`, `{"type":"toaster"}`, `{"type":"plug","power":5}`} {
+		if _, err := UnmarshalStatus([]byte(b)); !errors.Is(err, ErrDecode) {
+			t.Errorf("UnmarshalStatus(%s): err = %v, want ErrDecode", b, err)
+		}
+	}
+}
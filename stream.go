@@ -0,0 +1,144 @@
+package switchbot
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// Backpressure decides what an event stream does with an event when its
+// consumer isn't keeping up and the buffer is full.
+type Backpressure int
+
+const (
+	// BackpressureBlock holds the webhook request until the consumer takes
+	// the event or the stream's context is done.
+	BackpressureBlock Backpressure = iota
+	// BackpressureDrop discards the event. SwitchBot still gets a 200.
+	BackpressureDrop
+)
+
+// WithEventStream makes the receiver publish every event on the channel
+// returned by EventStream, buffered to hold buffer events, in addition to
+// calling its handler if one was given. The channel is closed once ctx is
+// done; events arriving after that are dropped.
+func WithEventStream(ctx context.Context, buffer int, policy Backpressure) ReceiverOption {
+	return func(r *WebhookReceiver) {
+		r.stream = newEventStream(ctx, buffer, policy)
+	}
+}
+
+// EventStream returns the channel events are published on, or nil if the
+// receiver was created without WithEventStream.
+func (r *WebhookReceiver) EventStream() <-chan WebhookEvent {
+	if r.stream == nil {
+		return nil
+	}
+	return r.stream.ch
+}
+
+type eventStream struct {
+	ctx    context.Context
+	policy Backpressure
+
+	// mu is held for reading while publishing and for writing while closing,
+	// so ch is never sent on after it is closed.
+	mu     sync.RWMutex
+	closed bool
+	ch     chan WebhookEvent
+}
+
+func newEventStream(ctx context.Context, buffer int, policy Backpressure) *eventStream {
+	s := &eventStream{
+		ctx:    ctx,
+		policy: policy,
+		ch:     make(chan WebhookEvent, buffer),
+	}
+	go func() {
+		<-ctx.Done()
+		s.mu.Lock()
+		s.closed = true
+		close(s.ch)
+		s.mu.Unlock()
+	}()
+	return s
+}
+
+func (s *eventStream) publish(event WebhookEvent) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return
+	}
+
+	if s.policy == BackpressureDrop {
+		select {
+		case s.ch <- event:
+		default:
+		}
+		return
+	}
+
+	select {
+	case s.ch <- event:
+	case <-s.ctx.Done():
+	}
+}
+
+// EventsFor subscribes to the events of the given devices, returning a
+// channel buffered to hold buffer events that receives only events whose
+// deviceMac matches one of deviceIDs, compared case-insensitively. policy
+// decides what happens when this subscriber falls behind, without affecting
+// other subscribers unless it is BackpressureBlock. Cancelling ctx
+// unsubscribes and closes the channel.
+//
+// Subscriptions work alongside the receiver's handler and WithEventStream.
+func (r *WebhookReceiver) EventsFor(ctx context.Context, buffer int, policy Backpressure, deviceIDs ...string) <-chan WebhookEvent {
+	sub := &subscription{
+		ids:    make(map[string]bool, len(deviceIDs)),
+		stream: newEventStream(ctx, buffer, policy),
+	}
+	for _, id := range deviceIDs {
+		sub.ids[strings.ToUpper(id)] = true
+	}
+
+	r.subsMu.Lock()
+	if r.subs == nil {
+		r.subs = make(map[*subscription]bool)
+	}
+	r.subs[sub] = true
+	r.subsMu.Unlock()
+
+	context.AfterFunc(ctx, func() {
+		r.subsMu.Lock()
+		delete(r.subs, sub)
+		r.subsMu.Unlock()
+	})
+	return sub.stream.ch
+}
+
+// subscription is one EventsFor subscriber.
+type subscription struct {
+	ids    map[string]bool // upper-cased device ids
+	stream *eventStream
+}
+
+// fanOut publishes event to every subscriber interested in its device. The
+// subscribers are copied first so a blocking subscriber doesn't hold up
+// subscribing and unsubscribing.
+func (r *WebhookReceiver) fanOut(event WebhookEvent) {
+	id := strings.ToUpper(event.Context.DeviceMac)
+
+	r.subsMu.Lock()
+	var matched []*subscription
+	for sub := range r.subs {
+		if sub.ids[id] {
+			matched = append(matched, sub)
+		}
+	}
+	r.subsMu.Unlock()
+
+	for _, sub := range matched {
+		sub.stream.publish(event)
+	}
+}
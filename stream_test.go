@@ -0,0 +1,208 @@
+package switchbot
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// receive reads one event from ch, failing the test if none arrives soon.
+func receive(t *testing.T, ch <-chan WebhookEvent) WebhookEvent {
+	t.Helper()
+	select {
+	case e, ok := <-ch:
+		if !ok {
+			t.Fatal("channel closed")
+		}
+		return e
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+	}
+	return WebhookEvent{}
+}
+
+// waitClosed fails the test unless ch is closed soon, draining it first.
+func waitClosed(t *testing.T, ch <-chan WebhookEvent) {
+	t.Helper()
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("channel not closed")
+		}
+	}
+}
+
+func TestEventStream(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := NewWebhookReceiver(nil, WithEventStream(ctx, 4, BackpressureBlock))
+
+	deliver(r, eventBody("DEV000000001", 1700000000000))
+	deliver(r, eventBody("DEV000000002", 1700000001000))
+
+	ch := r.EventStream()
+	if e := receive(t, ch); e.Context.DeviceMac != "DEV000000001" {
+		t.Errorf("first event from %s", e.Context.DeviceMac)
+	}
+	if e := receive(t, ch); e.Context.DeviceMac != "DEV000000002" {
+		t.Errorf("second event from %s", e.Context.DeviceMac)
+	}
+
+	cancel()
+	waitClosed(t, ch)
+
+	// Deliveries after shutdown are still acknowledged
+	if code := deliver(r, eventBody("DEV000000001", 1700000002000)); code != 200 {
+		t.Errorf("delivery after shutdown: status %d", code)
+	}
+}
+
+func TestEventStreamDrop(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r := NewWebhookReceiver(nil, WithEventStream(ctx, 1, BackpressureDrop))
+
+	done := make(chan struct{})
+	go func() {
+		for i := int64(0); i < 3; i++ {
+			deliver(r, eventBody("DEV000000001", 1700000000000+i))
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("deliveries blocked on a full stream with BackpressureDrop")
+	}
+
+	if e := receive(t, r.EventStream()); e.Context.TimeOfSample != 1700000000000 {
+		t.Errorf("kept event sampled at %d, want the first", e.Context.TimeOfSample)
+	}
+	select {
+	case e := <-r.EventStream():
+		t.Errorf("got dropped event %+v", e)
+	default:
+	}
+}
+
+func TestEventStreamBlockReleasedOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := NewWebhookReceiver(nil, WithEventStream(ctx, 0, BackpressureBlock))
+
+	done := make(chan struct{})
+	go func() {
+		deliver(r, eventBody("DEV000000001", 1700000000000))
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("delivery didn't wait for the consumer")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("blocked delivery not released by cancelling the stream")
+	}
+}
+
+func TestEventStreamDisabled(t *testing.T) {
+	if ch := NewWebhookReceiver(func(WebhookEvent) {}).EventStream(); ch != nil {
+		t.Error("EventStream without WithEventStream is not nil")
+	}
+}
+
+func TestEventsFor(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var handled int
+	r := NewWebhookReceiver(func(WebhookEvent) { handled++ })
+
+	one := r.EventsFor(ctx, 4, BackpressureBlock, "dev000000001")
+	others := r.EventsFor(ctx, 4, BackpressureBlock, "DEV000000002", "DEV000000003")
+
+	for i, mac := range []string{"DEV000000001", "DEV000000002", "DEV000000004", "DEV000000003"} {
+		deliver(r, eventBody(mac, 1700000000000+int64(i)))
+	}
+
+	if e := receive(t, one); e.Context.DeviceMac != "DEV000000001" {
+		t.Errorf("one got an event from %s", e.Context.DeviceMac)
+	}
+	for _, want := range []string{"DEV000000002", "DEV000000003"} {
+		if e := receive(t, others); e.Context.DeviceMac != want {
+			t.Errorf("others got an event from %s, want %s", e.Context.DeviceMac, want)
+		}
+	}
+	for name, ch := range map[string]<-chan WebhookEvent{"one": one, "others": others} {
+		select {
+		case e := <-ch:
+			t.Errorf("%s got unexpected event from %s", name, e.Context.DeviceMac)
+		default:
+		}
+	}
+	if handled != 4 {
+		t.Errorf("handler called %d times, want every event", handled)
+	}
+}
+
+func TestEventsForUnsubscribe(t *testing.T) {
+	r := NewWebhookReceiver(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := r.EventsFor(ctx, 1, BackpressureBlock, "DEV000000001")
+
+	cancel()
+	waitClosed(t, ch)
+	deadline := time.Now().Add(time.Second)
+	for {
+		r.subsMu.Lock()
+		n := len(r.subs)
+		r.subsMu.Unlock()
+		if n == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("subscription not removed after cancel")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if code := deliver(r, eventBody("DEV000000001", 1700000000000)); code != 200 {
+		t.Errorf("delivery after unsubscribing: status %d", code)
+	}
+}
+
+func TestEventsForSlowConsumerDropped(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r := NewWebhookReceiver(nil)
+	slow := r.EventsFor(ctx, 0, BackpressureDrop, "DEV000000001")
+	fast := r.EventsFor(ctx, 3, BackpressureBlock, "DEV000000001")
+
+	done := make(chan struct{})
+	go func() {
+		for i := int64(0); i < 3; i++ {
+			deliver(r, eventBody("DEV000000001", 1700000000000+i))
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("a slow BackpressureDrop subscriber blocked deliveries")
+	}
+	for i := int64(0); i < 3; i++ {
+		if e := receive(t, fast); e.Context.TimeOfSample != UnixMillis(1700000000000+i) {
+			t.Errorf("fast subscriber event %d sampled at %d", i, e.Context.TimeOfSample)
+		}
+	}
+	select {
+	case e := <-slow:
+		t.Errorf("slow subscriber kept event %+v, want it dropped", e)
+	default:
+	}
+}
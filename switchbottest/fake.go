@@ -0,0 +1,266 @@
+// Package switchbottest provides an in-memory fake of the SwitchBot API for
+// integration tests that shouldn't need the cloud or real devices.
+//
+//	fake := switchbottest.New()
+//	fake.AddBot("DEV1", "Kettle")
+//	fake.AddMeter("DEV2", "Hall", 21.5, 40)
+//	runAutomation(ctx, fake) // takes a switchbot.SwitchBot
+//
+// Commands change the fake devices' state and status reads reflect it. Errors
+// mimic the API's: an unknown device id fails with an *switchbot.APIError
+// matching switchbot.ErrDeviceNotFound.
+package switchbottest
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"switchbot"
+)
+
+// API statusCodes returned by the fake.
+const (
+	statusDeviceNotFound = 152
+	statusCommandError   = 160
+	statusSceneNotFound  = 190
+)
+
+// Fake is an in-memory switchbot.SwitchBot. Devices are added with the Add
+// methods; the zero value is not usable, call New. A Fake is safe for
+// concurrent use.
+type Fake struct {
+	mu       sync.Mutex
+	devices  []switchbot.Device
+	statuses map[string]any // typed status per device id
+	scenes   []switchbot.Scene
+	commands []SentCommand
+	executed []string
+}
+
+// SentCommand is a command received by the fake, as returned by Commands.
+type SentCommand struct {
+	DeviceID string
+	Command  switchbot.Command
+}
+
+var _ switchbot.SwitchBot = (*Fake)(nil)
+
+// New returns a Fake with no devices.
+func New() *Fake {
+	return &Fake{statuses: make(map[string]any)}
+}
+
+func (f *Fake) add(id, name string, t switchbot.DeviceType, status any) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.devices = append(f.devices, switchbot.Device{
+		DeviceID:           id,
+		DeviceName:         name,
+		DeviceType:         t,
+		EnableCloudService: true,
+	})
+	f.statuses[id] = status
+}
+
+// AddBot adds a Bot in switch mode, powered off.
+func (f *Fake) AddBot(id, name string) {
+	s := &switchbot.BotStatus{Power: switchbot.PowerOff, Battery: 100, DeviceMode: switchbot.BotModeSwitch}
+	s.DeviceID, s.DeviceType = id, switchbot.DeviceTypeBot
+	f.add(id, name, switchbot.DeviceTypeBot, s)
+}
+
+// AddCurtain adds a calibrated Curtain, fully open.
+func (f *Fake) AddCurtain(id, name string) {
+	s := &switchbot.CurtainStatus{Calibrate: true, Battery: 100}
+	s.DeviceID, s.DeviceType = id, switchbot.DeviceTypeCurtain
+	f.add(id, name, switchbot.DeviceTypeCurtain, s)
+}
+
+// AddMeter adds a Meter Plus with the given readings.
+func (f *Fake) AddMeter(id, name string, temperature float64, humidity int) {
+	s := &switchbot.MeterStatus{
+		Temperature: switchbot.FlexFloat(temperature),
+		Humidity:    switchbot.FlexInt(humidity),
+		Battery:     100,
+	}
+	s.DeviceID, s.DeviceType = id, switchbot.DeviceTypeMeterPlus
+	f.add(id, name, switchbot.DeviceTypeMeterPlus, s)
+}
+
+// SetMeter changes a meter's readings, as if the room had warmed up. It
+// panics if id isn't a meter added with AddMeter.
+func (f *Fake) SetMeter(id string, temperature float64, humidity int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s := f.statuses[id].(*switchbot.MeterStatus)
+	s.Temperature = switchbot.FlexFloat(temperature)
+	s.Humidity = switchbot.FlexInt(humidity)
+}
+
+// AddScene adds a manual scene. Executing it is recorded but changes no
+// device.
+func (f *Fake) AddScene(id, name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.scenes = append(f.scenes, switchbot.Scene{SceneID: id, SceneName: name})
+}
+
+// Commands returns the commands accepted so far, oldest first.
+func (f *Fake) Commands() []SentCommand {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.commands)
+}
+
+// ExecutedScenes returns the ids of the scenes executed so far, oldest
+// first.
+func (f *Fake) ExecutedScenes() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.executed)
+}
+
+// Devices lists the fake devices in the order they were added. Like the
+// API client, it returns switchbot.ErrNoDevices if there are none.
+func (f *Fake) Devices(ctx context.Context) ([]switchbot.Device, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.devices) == 0 {
+		return nil, switchbot.ErrNoDevices
+	}
+	return slices.Clone(f.devices), nil
+}
+
+// InfraredRemotes returns no remotes; the fake has none.
+func (f *Fake) InfraredRemotes(ctx context.Context) ([]switchbot.InfraredRemote, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return []switchbot.InfraredRemote{}, nil
+}
+
+// DeviceStatus returns a copy of the device's typed status, e.g.
+// *switchbot.BotStatus, as the client does.
+func (f *Fake) DeviceStatus(ctx context.Context, id string) (any, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch s := f.statuses[id].(type) {
+	case *switchbot.BotStatus:
+		c := *s
+		return &c, nil
+	case *switchbot.CurtainStatus:
+		c := *s
+		return &c, nil
+	case *switchbot.MeterStatus:
+		c := *s
+		return &c, nil
+	}
+	return nil, notFound(id)
+}
+
+// SendCommand applies cmd to the fake device. Bots accept turnOn, turnOff
+// and press; curtains accept turnOn (open), turnOff (close) and
+// setPosition; meters accept no commands. Other commands fail with
+// statusCode 160, as the API does. Call options are accepted and ignored.
+func (f *Fake) SendCommand(ctx context.Context, id string, cmd switchbot.Command, _ ...switchbot.CallOption) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var ok bool
+	switch s := f.statuses[id].(type) {
+	case *switchbot.BotStatus:
+		ok = applyBot(s, cmd)
+	case *switchbot.CurtainStatus:
+		ok = applyCurtain(s, cmd)
+	case *switchbot.MeterStatus:
+	default:
+		return notFound(id)
+	}
+	if !ok {
+		return &switchbot.APIError{StatusCode: statusCommandError, Message: fmt.Sprintf("unknown command %q", cmd.Command)}
+	}
+	f.commands = append(f.commands, SentCommand{DeviceID: id, Command: cmd})
+	return nil
+}
+
+func applyBot(s *switchbot.BotStatus, cmd switchbot.Command) bool {
+	switch cmd.Command {
+	case "turnOn":
+		s.Power = switchbot.PowerOn
+	case "turnOff":
+		s.Power = switchbot.PowerOff
+	case "press":
+		// A press moves the arm and returns; the power state is unchanged
+	default:
+		return false
+	}
+	return true
+}
+
+func applyCurtain(s *switchbot.CurtainStatus, cmd switchbot.Command) bool {
+	switch cmd.Command {
+	case "turnOn":
+		s.SlidePosition = 0
+	case "turnOff":
+		s.SlidePosition = 100
+	case "setPosition":
+		// "index,mode,position", e.g. "0,ff,50"
+		param, _ := cmd.Parameter.(string)
+		parts := strings.Split(param, ",")
+		if len(parts) != 3 {
+			return false
+		}
+		position, err := strconv.Atoi(parts[2])
+		if err != nil || position < 0 || position > 100 {
+			return false
+		}
+		s.SlidePosition = position
+	default:
+		return false
+	}
+	return true
+}
+
+// Scenes lists the scenes added with AddScene.
+func (f *Fake) Scenes(ctx context.Context) ([]switchbot.Scene, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.scenes), nil
+}
+
+// ExecuteScene records the execution of a scene added with AddScene. An
+// unknown id fails with an error matching switchbot.ErrSceneNotFound.
+func (f *Fake) ExecuteScene(ctx context.Context, id string) (*switchbot.SceneResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !slices.ContainsFunc(f.scenes, func(s switchbot.Scene) bool { return s.SceneID == id }) {
+		err := &switchbot.APIError{StatusCode: statusSceneNotFound, Message: "scene not found"}
+		return nil, fmt.Errorf("%w: %s: %w", switchbot.ErrSceneNotFound, id, err)
+	}
+	f.executed = append(f.executed, id)
+	return &switchbot.SceneResult{SceneID: id, Message: "success", Body: []byte("{}")}, nil
+}
+
+func notFound(id string) error {
+	return &switchbot.APIError{StatusCode: statusDeviceNotFound, Message: "device not found: " + id}
+}
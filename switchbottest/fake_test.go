@@ -0,0 +1,173 @@
+package switchbottest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"switchbot"
+)
+
+func TestFakeBot(t *testing.T) {
+	f := New()
+	f.AddBot("BOT01", "Kettle")
+	var sb switchbot.SwitchBot = f
+	ctx := context.Background()
+
+	if err := sb.SendCommand(ctx, "BOT01", switchbot.Command{Command: "turnOn"}); err != nil {
+		t.Fatal(err)
+	}
+	status, err := sb.DeviceStatus(ctx, "BOT01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bot, ok := status.(*switchbot.BotStatus)
+	if !ok || bot.Power != switchbot.PowerOn || bot.DeviceID != "BOT01" || bot.DeviceType != switchbot.DeviceTypeBot {
+		t.Fatalf("status = %#v, want a powered on Bot", status)
+	}
+
+	// The returned status is a copy
+	bot.Power = switchbot.PowerOff
+	if status, _ := sb.DeviceStatus(ctx, "BOT01"); status.(*switchbot.BotStatus).Power != switchbot.PowerOn {
+		t.Error("changing a returned status changed the fake")
+	}
+
+	if err := sb.SendCommand(ctx, "BOT01", switchbot.Command{Command: "press"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sb.SendCommand(ctx, "BOT01", switchbot.Command{Command: "setPosition"}); err == nil {
+		t.Error("unknown bot command accepted")
+	}
+	got := f.Commands()
+	if len(got) != 2 || got[0].Command.Command != "turnOn" || got[1].Command.Command != "press" {
+		t.Errorf("Commands = %+v, want the accepted turnOn and press", got)
+	}
+}
+
+func TestFakeCurtain(t *testing.T) {
+	f := New()
+	f.AddCurtain("CUR01", "Bedroom")
+	var sb switchbot.SwitchBot = f
+	ctx := context.Background()
+
+	position := func() int {
+		t.Helper()
+		status, err := sb.DeviceStatus(ctx, "CUR01")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return status.(*switchbot.CurtainStatus).SlidePosition
+	}
+	steps := []struct {
+		cmd  switchbot.Command
+		want int
+	}{
+		{switchbot.Command{Command: "turnOff"}, 100},
+		{switchbot.Command{Command: "setPosition", Parameter: "0,ff,40"}, 40},
+		{switchbot.Command{Command: "turnOn"}, 0},
+	}
+	for _, s := range steps {
+		if err := sb.SendCommand(ctx, "CUR01", s.cmd); err != nil {
+			t.Fatalf("%+v: %v", s.cmd, err)
+		}
+		if got := position(); got != s.want {
+			t.Errorf("after %+v: position %d, want %d", s.cmd, got, s.want)
+		}
+	}
+
+	for _, param := range []any{"0,ff,101", "50", 50} {
+		var apiErr *switchbot.APIError
+		err := sb.SendCommand(ctx, "CUR01", switchbot.Command{Command: "setPosition", Parameter: param})
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != statusCommandError {
+			t.Errorf("setPosition %v: err = %v, want statusCode %d", param, err, statusCommandError)
+		}
+	}
+}
+
+func TestFakeMeter(t *testing.T) {
+	f := New()
+	f.AddMeter("MET01", "Hall", 21.5, 40)
+	var sb switchbot.SwitchBot = f
+	ctx := context.Background()
+
+	f.SetMeter("MET01", 23, 55)
+	status, err := sb.DeviceStatus(ctx, "MET01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	meter, ok := status.(*switchbot.MeterStatus)
+	if !ok || meter.Temperature != 23 || meter.Humidity != 55 || meter.DeviceType != switchbot.DeviceTypeMeterPlus {
+		t.Errorf("status = %#v, want the updated readings", status)
+	}
+	if err := sb.SendCommand(ctx, "MET01", switchbot.Command{Command: "turnOn"}); err == nil {
+		t.Error("meter accepted a command")
+	}
+}
+
+func TestFakeDevices(t *testing.T) {
+	f := New()
+	var sb switchbot.SwitchBot = f
+	ctx := context.Background()
+
+	if _, err := sb.Devices(ctx); !errors.Is(err, switchbot.ErrNoDevices) {
+		t.Errorf("empty fake: err = %v, want ErrNoDevices", err)
+	}
+	f.AddBot("BOT01", "Kettle")
+	f.AddMeter("MET01", "Hall", 20, 50)
+	devices, err := sb.Devices(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(devices) != 2 || devices[0].DeviceID != "BOT01" || devices[1].DeviceType != switchbot.DeviceTypeMeterPlus {
+		t.Errorf("devices = %+v", devices)
+	}
+	if remotes, err := sb.InfraredRemotes(ctx); err != nil || len(remotes) != 0 {
+		t.Errorf("InfraredRemotes = %v, %v, want none", remotes, err)
+	}
+
+	if _, err := sb.DeviceStatus(ctx, "MISSING"); !errors.Is(err, switchbot.ErrDeviceNotFound) {
+		t.Errorf("status of unknown device: err = %v, want ErrDeviceNotFound", err)
+	}
+	if err := sb.SendCommand(ctx, "MISSING", switchbot.Command{Command: "turnOn"}); !errors.Is(err, switchbot.ErrDeviceNotFound) {
+		t.Errorf("command to unknown device: err = %v, want ErrDeviceNotFound", err)
+	}
+}
+
+func TestFakeScenes(t *testing.T) {
+	f := New()
+	f.AddScene("SCENE01", "Good Night")
+	var sb switchbot.SwitchBot = f
+	ctx := context.Background()
+
+	scenes, err := sb.Scenes(ctx)
+	if err != nil || len(scenes) != 1 || scenes[0].SceneName != "Good Night" {
+		t.Fatalf("Scenes = %+v, %v", scenes, err)
+	}
+	result, err := sb.ExecuteScene(ctx, "SCENE01")
+	if err != nil || result.SceneID != "SCENE01" || result.Message != "success" {
+		t.Errorf("ExecuteScene = %+v, %v", result, err)
+	}
+	if _, err := sb.ExecuteScene(ctx, "MISSING"); !errors.Is(err, switchbot.ErrSceneNotFound) {
+		t.Errorf("unknown scene: err = %v, want ErrSceneNotFound", err)
+	}
+	if got := f.ExecutedScenes(); len(got) != 1 || got[0] != "SCENE01" {
+		t.Errorf("ExecutedScenes = %v, want [SCENE01]", got)
+	}
+}
+
+func TestFakeHonoursContext(t *testing.T) {
+	f := New()
+	f.AddBot("BOT01", "Kettle")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := f.SendCommand(ctx, "BOT01", switchbot.Command{Command: "turnOn"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("SendCommand: err = %v, want context.Canceled", err)
+	}
+	if _, err := f.DeviceStatus(ctx, "BOT01"); !errors.Is(err, context.Canceled) {
+		t.Errorf("DeviceStatus: err = %v, want context.Canceled", err)
+	}
+	if len(f.Commands()) != 0 {
+		t.Error("command recorded despite the cancelled context")
+	}
+}
@@ -0,0 +1,81 @@
+package switchbot
+
+import (
+	"context"
+	"maps"
+	"time"
+)
+
+// DefaultCommandTimeouts returns the per-device-type command timeouts used by
+// WithCommandTimeouts(nil). Motorised devices get longer than switches, since
+// SwitchBot only answers once the device has acted, and a lock turning its
+// bolt or a curtain travelling can take many seconds. Types not listed use
+// the client's request timeout. The map is a fresh copy the caller may
+// modify.
+func DefaultCommandTimeouts() map[DeviceType]time.Duration {
+	return map[DeviceType]time.Duration{
+		DeviceTypeBot:             10 * time.Second,
+		DeviceTypePlug:            5 * time.Second,
+		DeviceTypePlugMiniUS:      5 * time.Second,
+		DeviceTypePlugMiniJP:      5 * time.Second,
+		DeviceTypeColorBulb:       5 * time.Second,
+		DeviceTypeStripLight:      5 * time.Second,
+		DeviceTypeStripLight3:     5 * time.Second,
+		DeviceTypeFloorLamp:       5 * time.Second,
+		DeviceTypeCeilingLight:    5 * time.Second,
+		DeviceTypeCeilingLightPro: 5 * time.Second,
+		DeviceTypeCurtain:         20 * time.Second,
+		DeviceTypeCurtain3:        20 * time.Second,
+		DeviceTypeBlindTilt:       20 * time.Second,
+		DeviceTypeLock:            30 * time.Second,
+		DeviceTypeLockPro:         30 * time.Second,
+		DeviceTypeInfraredRemote:  10 * time.Second,
+	}
+}
+
+// commandContext applies the command timeout for the device's type to ctx,
+// unless ctx already has a deadline or no timeout is configured for the
+// type. The type comes from the device list, so a lookup failure just leaves
+// ctx as is.
+func (c *Client) commandContext(ctx context.Context, id string) (context.Context, context.CancelFunc) {
+	if c.commandTimeouts == nil {
+		return ctx, func() {}
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+
+	list, err := c.deviceList(ctx)
+	if err != nil {
+		return ctx, func() {}
+	}
+	d := c.commandTimeouts[list.typeOf(id)]
+	if d <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, d)
+}
+
+// typeOf returns the type of the device with the given id,
+// DeviceTypeInfraredRemote for IR remotes, or "" if there is no such device.
+func (l *deviceList) typeOf(id string) DeviceType {
+	for _, d := range l.DeviceList {
+		if d.DeviceID == id {
+			return d.DeviceType
+		}
+	}
+	for _, r := range l.InfraredRemoteList {
+		if r.DeviceID == id {
+			return DeviceTypeInfraredRemote
+		}
+	}
+	return ""
+}
+
+// cloneTimeouts copies timeouts, substituting the defaults for nil.
+func cloneTimeouts(timeouts map[DeviceType]time.Duration) map[DeviceType]time.Duration {
+	if timeouts == nil {
+		return DefaultCommandTimeouts()
+	}
+	return maps.Clone(timeouts)
+}
@@ -0,0 +1,69 @@
+package switchbot
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDefaultCommandTimeouts(t *testing.T) {
+	d := DefaultCommandTimeouts()
+	if d[DeviceTypeLock] <= d[DeviceTypePlug] {
+		t.Errorf("lock timeout %v, want longer than plug timeout %v", d[DeviceTypeLock], d[DeviceTypePlug])
+	}
+	d[DeviceTypeLock] = 0
+	if DefaultCommandTimeouts()[DeviceTypeLock] == 0 {
+		t.Error("DefaultCommandTimeouts returned a shared map")
+	}
+}
+
+func TestCommandContext(t *testing.T) {
+	devices := []Device{
+		{DeviceID: "lock", DeviceType: DeviceTypeLock},
+		{DeviceID: "plug", DeviceType: DeviceTypePlug},
+		{DeviceID: "meter", DeviceType: DeviceTypeMeter},
+	}
+	c, _ := newDeviceClient(t, devices, nil, WithCommandTimeouts(nil))
+
+	remaining := func(ctx context.Context, id string) time.Duration {
+		ctx, cancel := c.commandContext(ctx, id)
+		defer cancel()
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			return 0
+		}
+		return time.Until(deadline)
+	}
+
+	lock, plug := remaining(context.Background(), "lock"), remaining(context.Background(), "plug")
+	if lock <= 25*time.Second || lock > 30*time.Second {
+		t.Errorf("lock deadline in %v, want about 30s", lock)
+	}
+	if plug <= 0 || plug > 5*time.Second {
+		t.Errorf("plug deadline in %v, want about 5s", plug)
+	}
+	if d := remaining(context.Background(), "meter"); d != 0 {
+		t.Errorf("meter got a deadline in %v, want none for an unlisted type", d)
+	}
+	if d := remaining(context.Background(), "missing"); d != 0 {
+		t.Errorf("unknown device got a deadline in %v, want none", d)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	if d := remaining(ctx, "lock"); d < 59*time.Minute {
+		t.Errorf("lock deadline in %v, want the caller's deadline kept", d)
+	}
+}
+
+func TestWithCommandTimeoutsCopiesMap(t *testing.T) {
+	timeouts := map[DeviceType]time.Duration{DeviceTypePlug: time.Second}
+	c, _ := newDeviceClient(t, []Device{{DeviceID: "plug", DeviceType: DeviceTypePlug}}, nil, WithCommandTimeouts(timeouts))
+	timeouts[DeviceTypePlug] = time.Hour
+
+	ctx, cancel := c.commandContext(context.Background(), "plug")
+	defer cancel()
+	if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) > time.Second {
+		t.Errorf("deadline = %v, %v, want the timeout given to WithCommandTimeouts", deadline, ok)
+	}
+}
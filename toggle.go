@@ -0,0 +1,44 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrNotToggleable is returned by Toggle for devices without a readable
+// power state, including Bots in press mode.
+var ErrNotToggleable = errors.New("device has no power state to toggle")
+
+// Toggle reads the power state of a Bot in switch mode, a plug or a light
+// and sends the opposite command.
+func (c *Client) Toggle(ctx context.Context, id string) error {
+	status, err := c.DeviceStatus(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	var power PowerState
+	switch s := status.(type) {
+	case *BotStatus:
+		if s.DeviceMode == BotModePress {
+			return fmt.Errorf("%w: bot %s is in press mode", ErrNotToggleable, id)
+		}
+		power = s.Power
+	case *PlugStatus:
+		power = s.Power
+	case *LightStatus:
+		power = s.Power
+	default:
+		return fmt.Errorf("%w: %s", ErrNotToggleable, id)
+	}
+
+	switch power {
+	case PowerOn:
+		return c.SendCommand(ctx, id, Command{Command: "turnOff", Parameter: DefaultParameter})
+	case PowerOff:
+		return c.SendCommand(ctx, id, Command{Command: "turnOn", Parameter: DefaultParameter})
+	default:
+		return fmt.Errorf("%w: %s reports power %q", ErrNotToggleable, id, power)
+	}
+}
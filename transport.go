@@ -0,0 +1,22 @@
+package switchbot
+
+import (
+	"net/http"
+	"time"
+)
+
+// transportConfig holds the settings of WithTransportConfig.
+type transportConfig struct {
+	maxIdleConns        int
+	maxIdleConnsPerHost int
+	idleTimeout         time.Duration
+}
+
+// apply returns a copy of http.DefaultTransport with the settings applied.
+func (tc *transportConfig) apply() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.MaxIdleConns = tc.maxIdleConns
+	t.MaxIdleConnsPerHost = tc.maxIdleConnsPerHost
+	t.IdleConnTimeout = tc.idleTimeout
+	return t
+}
@@ -0,0 +1,44 @@
+package switchbot
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestTransportConfig(t *testing.T) {
+	c, err := NewClient(testToken, testSecret, WithTransportConfig(50, 20, 30*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr, ok := c.httpClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("transport is %T, want *http.Transport", c.httpClient.Transport)
+	}
+	if tr.MaxIdleConns != 50 || tr.MaxIdleConnsPerHost != 20 || tr.IdleConnTimeout != 30*time.Second {
+		t.Errorf("transport settings = %d, %d, %v", tr.MaxIdleConns, tr.MaxIdleConnsPerHost, tr.IdleConnTimeout)
+	}
+	if tr == http.DefaultTransport {
+		t.Error("http.DefaultTransport modified instead of a copy")
+	}
+	if def := http.DefaultTransport.(*http.Transport); def.MaxIdleConnsPerHost == 20 {
+		t.Error("http.DefaultTransport settings changed")
+	}
+}
+
+func TestTransportConfigIgnoredWithHTTPClient(t *testing.T) {
+	for _, order := range []string{"before", "after"} {
+		own := &http.Client{}
+		opts := []Option{WithHTTPClient(own), WithTransportConfig(50, 20, time.Second)}
+		if order == "before" {
+			opts = []Option{WithTransportConfig(50, 20, time.Second), WithHTTPClient(own)}
+		}
+		c, err := NewClient(testToken, testSecret, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.httpClient != own || own.Transport != nil {
+			t.Errorf("WithTransportConfig %s WithHTTPClient changed the caller's client", order)
+		}
+	}
+}
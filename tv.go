@@ -0,0 +1,42 @@
+package switchbot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// TVVolumeUp raises the volume of an IR TV or set top box remote.
+func (c *Client) TVVolumeUp(ctx context.Context, remoteID string) error {
+	return c.SendCommand(ctx, remoteID, Command{Command: "volumeAdd", Parameter: DefaultParameter})
+}
+
+// TVVolumeDown lowers the volume of an IR TV or set top box remote.
+func (c *Client) TVVolumeDown(ctx context.Context, remoteID string) error {
+	return c.SendCommand(ctx, remoteID, Command{Command: "volumeSub", Parameter: DefaultParameter})
+}
+
+// TVChannelUp switches an IR TV or set top box remote to the next channel.
+func (c *Client) TVChannelUp(ctx context.Context, remoteID string) error {
+	return c.SendCommand(ctx, remoteID, Command{Command: "channelAdd", Parameter: DefaultParameter})
+}
+
+// TVChannelDown switches an IR TV or set top box remote to the previous
+// channel.
+func (c *Client) TVChannelDown(ctx context.Context, remoteID string) error {
+	return c.SendCommand(ctx, remoteID, Command{Command: "channelSub", Parameter: DefaultParameter})
+}
+
+// TVSetChannel switches an IR TV or set top box remote to channel n.
+func (c *Client) TVSetChannel(ctx context.Context, remoteID string, n int) error {
+	if n < 1 {
+		return fmt.Errorf("%w: channel must be positive, got %d", ErrInvalidParameter, n)
+	}
+	return c.SendCommand(ctx, remoteID, Command{Command: "SetChannel", Parameter: strconv.Itoa(n)})
+}
+
+// TVMute toggles mute. SwitchBot documents setMute for DVD and speaker
+// remotes; TV remotes only honour it if the learned remote has a mute key.
+func (c *Client) TVMute(ctx context.Context, remoteID string) error {
+	return c.SendCommand(ctx, remoteID, Command{Command: "setMute", Parameter: DefaultParameter})
+}
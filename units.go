@@ -0,0 +1,29 @@
+package switchbot
+
+import "math"
+
+// TemperatureUnit is the unit temperatures are given in.
+type TemperatureUnit string
+
+const (
+	Celsius    TemperatureUnit = "C"
+	Fahrenheit TemperatureUnit = "F"
+)
+
+// FahrenheitToCelsius converts degrees Fahrenheit to Celsius.
+func FahrenheitToCelsius(f float64) float64 {
+	return (f - 32) * 5 / 9
+}
+
+// CelsiusToFahrenheit converts degrees Celsius to Fahrenheit.
+func CelsiusToFahrenheit(c float64) float64 {
+	return c*9/5 + 32
+}
+
+// roundCelsius converts t in unit to the nearest whole degree Celsius.
+func roundCelsius(t int, unit TemperatureUnit) int {
+	if unit != Fahrenheit {
+		return t
+	}
+	return int(math.Round(FahrenheitToCelsius(float64(t))))
+}
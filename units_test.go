@@ -0,0 +1,34 @@
+package switchbot
+
+import "testing"
+
+func TestTemperatureConversion(t *testing.T) {
+	for _, tt := range []struct{ c, f float64 }{{0, 32}, {100, 212}, {-40, -40}, {25, 77}} {
+		if got := CelsiusToFahrenheit(tt.c); got != tt.f {
+			t.Errorf("CelsiusToFahrenheit(%v) = %v, want %v", tt.c, got, tt.f)
+		}
+		if got := FahrenheitToCelsius(tt.f); got != tt.c {
+			t.Errorf("FahrenheitToCelsius(%v) = %v, want %v", tt.f, got, tt.c)
+		}
+	}
+}
+
+func TestRoundCelsius(t *testing.T) {
+	tests := []struct {
+		t    int
+		unit TemperatureUnit
+		want int
+	}{
+		{70, Fahrenheit, 21},
+		{71, Fahrenheit, 22},
+		{77, Fahrenheit, 25},
+		{-4, Fahrenheit, -20},
+		{21, Celsius, 21},
+		{21, "", 21},
+	}
+	for _, tt := range tests {
+		if got := roundCelsius(tt.t, tt.unit); got != tt.want {
+			t.Errorf("roundCelsius(%d, %q) = %d, want %d", tt.t, tt.unit, got, tt.want)
+		}
+	}
+}
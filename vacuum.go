@@ -0,0 +1,55 @@
+package switchbot
+
+import "context"
+
+// VacuumWorkingStatus is what a robot vacuum is doing.
+type VacuumWorkingStatus string
+
+const (
+	VacuumStandBy        VacuumWorkingStatus = "StandBy"
+	VacuumCleaning       VacuumWorkingStatus = "Clearing"
+	VacuumPaused         VacuumWorkingStatus = "Paused"
+	VacuumGoingToDock    VacuumWorkingStatus = "GotoChargeBase"
+	VacuumCharging       VacuumWorkingStatus = "Charging"
+	VacuumChargeDone     VacuumWorkingStatus = "ChargeDone"
+	VacuumDormant        VacuumWorkingStatus = "Dormant"
+	VacuumInTrouble      VacuumWorkingStatus = "InTrouble"
+	VacuumRemoteControl  VacuumWorkingStatus = "InRemoteControl"
+	VacuumDustCollecting VacuumWorkingStatus = "InDustCollecting"
+)
+
+// VacuumStatus is the status of a robot vacuum.
+//
+// SwitchBot doesn't expose cleaning maps or rooms through the API. Every
+// model reports WorkingStatus, OnlineStatus and Battery; WaterBaseBattery and
+// TaskType are only reported by the S10, and fields reported by future
+// models will be added as optional fields.
+type VacuumStatus struct {
+	BaseStatus
+	Version       string              `json:"version,omitempty"`
+	WorkingStatus VacuumWorkingStatus `json:"workingStatus"`
+	// OnlineStatus is "online" or "offline".
+	OnlineStatus string  `json:"onlineStatus"`
+	Battery      Battery `json:"battery"`
+
+	WaterBaseBattery *Battery `json:"waterBaseBattery,omitempty"`
+	// TaskType is e.g. "standBy", "explore" or "cleanAll".
+	TaskType string `json:"taskType,omitempty"`
+}
+
+// Online reports whether the vacuum is connected.
+func (s *VacuumStatus) Online() bool {
+	return s.OnlineStatus == "online"
+}
+
+// MarshalJSON adds the "type" field UnmarshalStatus uses to restore the
+// status.
+func (s VacuumStatus) MarshalJSON() ([]byte, error) {
+	type plain VacuumStatus
+	return marshalStatus(statusKindVacuum, plain(s))
+}
+
+// VacuumStatus fetches the status of the robot vacuum with the given id.
+func (c *Client) VacuumStatus(ctx context.Context, id string) (*VacuumStatus, error) {
+	return typedStatus[VacuumStatus](ctx, c, id)
+}
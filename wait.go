@@ -0,0 +1,50 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultPollInterval is the time between status reads in WaitForState.
+const DefaultPollInterval = 2 * time.Second
+
+// ErrStateTimeout is returned by WaitForState when the condition isn't met
+// in time.
+var ErrStateTimeout = errors.New("timed out waiting for device state")
+
+// WaitForState polls the typed status of a device (as returned by
+// DeviceStatus) until cond reports true, and returns that status. It gives up
+// with ErrStateTimeout once timeout has passed; a timeout of 0 waits until ctx
+// is done. Failed reads are retried until the deadline, the last error being
+// included in the timeout error.
+func (c *Client) WaitForState(ctx context.Context, id string, cond func(status any) bool, timeout time.Duration) (any, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var lastErr error
+	for {
+		status, err := c.DeviceStatus(ctx, id)
+		if err == nil && cond(status) {
+			return status, nil
+		}
+		// A read cut short by the deadline says nothing about the device
+		if err != nil && ctx.Err() == nil {
+			lastErr = err
+		}
+
+		if err := sleep(ctx, c.pollInterval); err != nil {
+			if !errors.Is(err, context.DeadlineExceeded) {
+				return nil, err
+			}
+			if lastErr != nil {
+				return nil, fmt.Errorf("%w: %s: last error: %v", ErrStateTimeout, id, lastErr)
+			}
+			return nil, fmt.Errorf("%w: %s", ErrStateTimeout, id)
+		}
+	}
+}
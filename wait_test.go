@@ -0,0 +1,78 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWaitForState(t *testing.T) {
+	var reads atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		power := "off"
+		if reads.Add(1) >= 3 {
+			power = "on"
+		}
+		writeEnvelope(w, statusSuccess, map[string]any{"deviceId": "BOT01", "deviceType": "Bot", "power": power})
+	}, WithPollInterval(time.Millisecond))
+
+	status, err := c.WaitForState(context.Background(), "BOT01", poweredOn, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !poweredOn(status) || reads.Load() != 3 {
+		t.Errorf("status = %+v after %d reads, want the bot on after 3", status, reads.Load())
+	}
+}
+
+func TestWaitForStateTimeout(t *testing.T) {
+	var reads atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		reads.Add(1)
+		writeEnvelope(w, statusSuccess, map[string]any{"deviceId": "BOT01", "deviceType": "Bot", "power": "off"})
+	}, WithPollInterval(5*time.Millisecond))
+
+	start := time.Now()
+	_, err := c.WaitForState(context.Background(), "BOT01", poweredOn, 50*time.Millisecond)
+	if !errors.Is(err, ErrStateTimeout) {
+		t.Fatalf("err = %v, want ErrStateTimeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("returned after %v, want about the 50ms timeout", elapsed)
+	}
+	if reads.Load() < 2 {
+		t.Errorf("%d status reads, want polling until the timeout", reads.Load())
+	}
+}
+
+func TestWaitForStateTimeoutLastError(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeAPIError(w, 161, "device offline")
+	}, WithPollInterval(5*time.Millisecond))
+
+	_, err := c.WaitForState(context.Background(), "BOT01", poweredOn, 30*time.Millisecond)
+	if !errors.Is(err, ErrStateTimeout) || !strings.Contains(err.Error(), "device offline") {
+		t.Errorf("err = %v, want ErrStateTimeout with the last read error", err)
+	}
+}
+
+func TestWaitForStateCanceled(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeEnvelope(w, statusSuccess, map[string]any{"deviceId": "BOT01", "deviceType": "Bot", "power": "off"})
+	}, WithPollInterval(5*time.Millisecond))
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(30*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := c.WaitForState(ctx, "BOT01", poweredOn, 0)
+	if !errors.Is(err, context.Canceled) || errors.Is(err, ErrStateTimeout) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("returned %v after the cancellation, want promptly", elapsed)
+	}
+}
@@ -0,0 +1,39 @@
+package switchbot
+
+import "context"
+
+// Values of WaterLeakStatus.Status.
+const (
+	WaterDry  = 0
+	WaterLeak = 1
+)
+
+// WaterLeakStatus is the status of a Water Leak Detector.
+//
+// The status body carries no event time; the water leak webhook event's
+// TimeOfSample is the only record of when a leak was detected.
+type WaterLeakStatus struct {
+	BaseStatus
+	Version string  `json:"version,omitempty"`
+	Battery Battery `json:"battery"`
+	// Status is WaterLeak while water is detected, WaterDry otherwise.
+	Status int `json:"status"`
+}
+
+// Leaking reports whether the detector currently senses water.
+func (s *WaterLeakStatus) Leaking() bool {
+	return s.Status == WaterLeak
+}
+
+// MarshalJSON adds the "type" field UnmarshalStatus uses to restore the
+// status.
+func (s WaterLeakStatus) MarshalJSON() ([]byte, error) {
+	type plain WaterLeakStatus
+	return marshalStatus(statusKindWaterLeak, plain(s))
+}
+
+// WaterLeakStatus fetches the status of the Water Leak Detector with the
+// given id.
+func (c *Client) WaterLeakStatus(ctx context.Context, id string) (*WaterLeakStatus, error) {
+	return typedStatus[WaterLeakStatus](ctx, c, id)
+}
@@ -0,0 +1,153 @@
+package switchbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// webhookAllDevices is the deviceList value subscribing a webhook to every
+// device on the account.
+const webhookAllDevices = "ALL"
+
+// ErrWebhookLimit is returned when setting up a webhook while a different
+// URL is already configured. SwitchBot allows one webhook URL per token.
+var ErrWebhookLimit = errors.New("a webhook URL is already configured for this token")
+
+// Webhook is a configured webhook URL and the devices it reports on.
+type Webhook struct {
+	URL string `json:"url"`
+	// DeviceList is "ALL" or a comma separated list of device ids.
+	DeviceList     string     `json:"deviceList"`
+	Enable         bool       `json:"enable"`
+	CreateTime     UnixMillis `json:"createTime"`
+	LastUpdateTime UnixMillis `json:"lastUpdateTime"`
+}
+
+// DeviceIDs returns the devices the webhook is scoped to, or nil if it
+// reports on all devices.
+func (w Webhook) DeviceIDs() []string {
+	if w.DeviceList == "" || strings.EqualFold(w.DeviceList, webhookAllDevices) {
+		return nil
+	}
+	return strings.Split(w.DeviceList, ",")
+}
+
+// webhookRequest is the body shared by the /webhook endpoints; each action
+// uses a subset of the fields.
+type webhookRequest struct {
+	Action     string         `json:"action"`
+	URL        string         `json:"url,omitempty"`
+	URLs       []string       `json:"urls,omitempty"`
+	DeviceList string         `json:"deviceList,omitempty"`
+	Config     *webhookConfig `json:"config,omitempty"`
+}
+
+// webhookConfig is the config object of an updateWebhook request.
+type webhookConfig struct {
+	URL    string `json:"url"`
+	Enable bool   `json:"enable"`
+}
+
+// WebhookResult confirms a webhook change.
+type WebhookResult struct {
+	URL string
+	// Message is the envelope message, normally "success".
+	Message string
+}
+
+// SetupWebhook registers url to receive events from every device.
+func (c *Client) SetupWebhook(ctx context.Context, url string) error {
+	return c.setupWebhook(ctx, url, webhookAllDevices)
+}
+
+// SetupWebhookForDevices registers url to receive events only from the given
+// devices, sent as a comma separated deviceList. SwitchBot only documents
+// deviceList "ALL": per-device lists are undocumented, and the API may reject
+// them or ignore the scope; use SetupWebhook and filter events if it does.
+func (c *Client) SetupWebhookForDevices(ctx context.Context, url string, deviceIDs []string) error {
+	if len(deviceIDs) == 0 {
+		return fmt.Errorf("%w: no device ids given", ErrInvalidParameter)
+	}
+	return c.setupWebhook(ctx, url, strings.Join(deviceIDs, ","))
+}
+
+func (c *Client) setupWebhook(ctx context.Context, url, deviceList string) error {
+	if url == "" {
+		return fmt.Errorf("%w: webhook url is empty", ErrInvalidParameter)
+	}
+
+	// Fail clearly rather than with an opaque API error if another URL holds
+	// the token's only webhook slot
+	existing, err := c.webhookURLs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, u := range existing {
+		if u != url {
+			return fmt.Errorf("%w: %s", ErrWebhookLimit, u)
+		}
+	}
+
+	return c.do(ctx, http.MethodPost, "/webhook/setupWebhook", webhookRequest{
+		Action:     "setupWebhook",
+		URL:        url,
+		DeviceList: deviceList,
+	}, nil)
+}
+
+// QueryWebhookURLs returns the configured webhooks with their device scope.
+func (c *Client) QueryWebhookURLs(ctx context.Context) ([]Webhook, error) {
+	urls, err := c.webhookURLs(ctx)
+	if err != nil || len(urls) == 0 {
+		return nil, err
+	}
+
+	var webhooks []Webhook
+	if err := c.do(ctx, http.MethodPost, "/webhook/queryWebhook", webhookRequest{
+		Action: "queryDetails",
+		URLs:   urls,
+	}, &webhooks); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// webhookURLs lists the configured webhook URLs.
+func (c *Client) webhookURLs(ctx context.Context) ([]string, error) {
+	var body struct {
+		URLs []string `json:"urls"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/webhook/queryWebhook", webhookRequest{Action: "queryUrl"}, &body); err != nil {
+		return nil, err
+	}
+	return body.URLs, nil
+}
+
+// UpdateWebhook enables or disables the webhook with the given url. A
+// disabled webhook stays configured but receives no events.
+func (c *Client) UpdateWebhook(ctx context.Context, url string, enable bool) (*WebhookResult, error) {
+	return c.changeWebhook(ctx, "/webhook/updateWebhook", url, webhookRequest{
+		Action: "updateWebhook",
+		Config: &webhookConfig{URL: url, Enable: enable},
+	})
+}
+
+// DeleteWebhook removes the webhook with the given url.
+func (c *Client) DeleteWebhook(ctx context.Context, url string) (*WebhookResult, error) {
+	return c.changeWebhook(ctx, "/webhook/deleteWebhook", url, webhookRequest{
+		Action: "deleteWebhook",
+		URL:    url,
+	})
+}
+
+// changeWebhook sends a webhook change and returns its confirmation.
+func (c *Client) changeWebhook(ctx context.Context, path, url string, req webhookRequest) (*WebhookResult, error) {
+	r := &apiRequest{method: http.MethodPost, path: path, payload: req}
+	if err := c.send(ctx, r); err != nil {
+		return nil, err
+	}
+	return &WebhookResult{URL: url, Message: r.respMessage}, nil
+}
@@ -0,0 +1,175 @@
+package switchbot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+// webhookServer fakes the /webhook endpoints for a token holding at most one
+// webhook.
+type webhookServer struct {
+	url        string
+	deviceList string
+	disabled   bool
+	setups     int
+}
+
+func (s *webhookServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req webhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	switch req.Action {
+	case "setupWebhook":
+		s.url, s.deviceList = req.URL, req.DeviceList
+		s.setups++
+		success(w, r)
+	case "queryUrl":
+		urls := []string{}
+		if s.url != "" {
+			urls = append(urls, s.url)
+		}
+		writeEnvelope(w, statusSuccess, map[string]any{"urls": urls})
+	case "queryDetails":
+		writeEnvelope(w, statusSuccess, []Webhook{{URL: s.url, DeviceList: s.deviceList, Enable: !s.disabled}})
+	case "updateWebhook":
+		if r.URL.Path != apiVersion+"/webhook/updateWebhook" || req.Config == nil || req.Config.URL != s.url {
+			writeAPIError(w, 190, "no such webhook")
+			return
+		}
+		s.disabled = !req.Config.Enable
+		success(w, r)
+	case "deleteWebhook":
+		if r.URL.Path != apiVersion+"/webhook/deleteWebhook" || req.URL != s.url {
+			writeAPIError(w, 190, "no such webhook")
+			return
+		}
+		s.url, s.deviceList = "", ""
+		success(w, r)
+	default:
+		http.Error(w, "unknown action", http.StatusBadRequest)
+	}
+}
+
+func TestSetupWebhookAllDevices(t *testing.T) {
+	srv := &webhookServer{}
+	c := newTestClient(t, srv.ServeHTTP)
+	ctx := context.Background()
+
+	if err := c.SetupWebhook(ctx, "https://example.com/hook"); err != nil {
+		t.Fatal(err)
+	}
+	webhooks, err := c.QueryWebhookURLs(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(webhooks) != 1 || webhooks[0].URL != "https://example.com/hook" || webhooks[0].DeviceList != "ALL" {
+		t.Fatalf("webhooks = %+v, want one for all devices", webhooks)
+	}
+	if ids := webhooks[0].DeviceIDs(); ids != nil {
+		t.Errorf("DeviceIDs = %v, want nil for ALL", ids)
+	}
+}
+
+func TestSetupWebhookForDevices(t *testing.T) {
+	srv := &webhookServer{}
+	c := newTestClient(t, srv.ServeHTTP)
+	ctx := context.Background()
+
+	if err := c.SetupWebhookForDevices(ctx, "https://example.com/hook", []string{"DEV000000001", "DEV000000002"}); err != nil {
+		t.Fatal(err)
+	}
+	if srv.deviceList != "DEV000000001,DEV000000002" {
+		t.Errorf("deviceList = %q", srv.deviceList)
+	}
+	webhooks, err := c.QueryWebhookURLs(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(webhooks) != 1 {
+		t.Fatalf("webhooks = %+v, want one", webhooks)
+	}
+	if ids := webhooks[0].DeviceIDs(); !slices.Equal(ids, []string{"DEV000000001", "DEV000000002"}) {
+		t.Errorf("DeviceIDs = %v", ids)
+	}
+
+	if err := c.SetupWebhookForDevices(ctx, "https://example.com/hook", nil); !errors.Is(err, ErrInvalidParameter) {
+		t.Errorf("no devices: err = %v, want ErrInvalidParameter", err)
+	}
+}
+
+func TestSetupWebhookLimit(t *testing.T) {
+	srv := &webhookServer{url: "https://example.com/old", deviceList: "ALL"}
+	c := newTestClient(t, srv.ServeHTTP)
+	ctx := context.Background()
+
+	if err := c.SetupWebhook(ctx, "https://example.com/new"); !errors.Is(err, ErrWebhookLimit) {
+		t.Fatalf("err = %v, want ErrWebhookLimit", err)
+	}
+	if srv.setups != 0 {
+		t.Errorf("setupWebhook was sent despite the limit")
+	}
+
+	// Setting up the existing URL again is allowed
+	if err := c.SetupWebhook(ctx, "https://example.com/old"); err != nil {
+		t.Errorf("same URL: %v", err)
+	}
+}
+
+func TestUpdateWebhook(t *testing.T) {
+	srv := &webhookServer{url: "https://example.com/hook", deviceList: "ALL"}
+	c := newTestClient(t, srv.ServeHTTP)
+	ctx := context.Background()
+
+	for _, enable := range []bool{false, true} {
+		result, err := c.UpdateWebhook(ctx, "https://example.com/hook", enable)
+		if err != nil {
+			t.Fatalf("enable %v: %v", enable, err)
+		}
+		if result.URL != "https://example.com/hook" || result.Message != "success" {
+			t.Errorf("enable %v: result = %+v", enable, result)
+		}
+		webhooks, err := c.QueryWebhookURLs(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(webhooks) != 1 || webhooks[0].Enable != enable {
+			t.Errorf("after UpdateWebhook(%v): webhooks = %+v", enable, webhooks)
+		}
+	}
+
+	if _, err := c.UpdateWebhook(ctx, "https://example.com/other", true); err == nil {
+		t.Error("updating an unknown webhook succeeded")
+	}
+}
+
+func TestDeleteWebhook(t *testing.T) {
+	srv := &webhookServer{url: "https://example.com/hook", deviceList: "ALL"}
+	c := newTestClient(t, srv.ServeHTTP)
+	ctx := context.Background()
+
+	result, err := c.DeleteWebhook(ctx, "https://example.com/hook")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.URL != "https://example.com/hook" || result.Message != "success" {
+		t.Errorf("result = %+v", result)
+	}
+	webhooks, err := c.QueryWebhookURLs(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(webhooks) != 0 {
+		t.Errorf("webhooks after delete = %+v, want none", webhooks)
+	}
+
+	// With the webhook gone, a new one can be set up
+	if err := c.SetupWebhook(ctx, "https://example.com/new"); err != nil {
+		t.Errorf("setup after delete: %v", err)
+	}
+}